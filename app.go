@@ -0,0 +1,59 @@
+package desktop
+
+import (
+	"github.com/eyasliu/desktop/go-webview2"
+)
+
+// AppOptions 是 App 的配置，KeepAlive 为 true 时最后一个窗口关闭后不退出
+type AppOptions = webview2.AppOptions
+
+// App 在同一个 UI 线程中管理多个窗口，每个窗口有唯一的 id，
+// 不需要为每个窗口单独启动 goroutine 和消息循环
+//
+//	app := desktop.NewApp(&desktop.AppOptions{})
+//	app.New("main", &desktop.Options{StartURL: "https://app.local/"})
+//	app.New("settings", &desktop.Options{StartURL: "https://app.local/settings"})
+//	app.Run()
+type App struct {
+	*webview2.App
+}
+
+// appOptions 返回 App 的配置，没有设置 Logger 时打印到标准输出
+func appOptions(opt *AppOptions) webview2.AppOptions {
+	var o webview2.AppOptions
+	if opt != nil {
+		o = *opt
+	}
+	if o.Logger == nil {
+		o.Logger = &defaultLogger{}
+	}
+	return o
+}
+
+// New 在 App 中新建一个 id 为 id 的窗口，id 为空时自动生成，id 已经存在时返回错误，
+// opt.Tray 在 App 中不生效，托盘需要单独通过 tray.Run 启动
+func (a *App) New(id string, opt *Options) (WebView, error) {
+	w, err := a.App.NewWindow(id, appWindowOptions(opt))
+	if err != nil {
+		return nil, err
+	}
+	return w.(WebView), nil
+}
+
+// Window 返回 id 对应的窗口，没有时返回 nil, false
+func (a *App) Window(id string) (WebView, bool) {
+	w, ok := a.App.Window(id)
+	if !ok {
+		return nil, false
+	}
+	return w.(WebView), true
+}
+
+// Windows 按创建顺序返回所有窗口
+func (a *App) Windows() []WebView {
+	list := []WebView{}
+	for _, w := range a.App.Windows() {
+		list = append(list, w.(WebView))
+	}
+	return list
+}
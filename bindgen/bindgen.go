@@ -0,0 +1,150 @@
+// Package bindgen 根据绑定到页面的 Go 函数生成 TypeScript 类型声明和 JS 调用模块，
+// 避免前端手写的 .d.ts 和 Go 函数签名不一致。
+//
+// 函数来源可以是已经注册的绑定（WebView.Bindings()），也可以是一个 Go 包中导出的函数：
+//
+//	g := bindgen.New()
+//	g.AddFuncs(app.Bindings())
+//	os.WriteFile("bindings.d.ts", g.DTS(), 0644)
+//	os.WriteFile("bindings.js", g.JS(), 0644)
+package bindgen
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Kind 是类型的种类
+type Kind int
+
+const (
+	Any Kind = iota
+	Boolean
+	Number
+	String
+	Array
+	Record
+	Object
+	// Ref 引用一个具名的结构体类型，声明在 Generator.Types 中
+	Ref
+)
+
+// Type 是和语言无关的类型描述，由 Go 类型转换而来
+type Type struct {
+	Kind Kind
+	// Elem 是 Array 和 Record 的元素类型
+	Elem *Type
+	// Name 是 Ref 引用的类型名
+	Name string
+	// Fields 是 Object 的字段
+	Fields []*Field
+	// Nullable 值是否可以为 null，如 Go 中的指针
+	Nullable bool
+}
+
+// Field 是结构体字段，名称取自 json tag
+type Field struct {
+	Name     string
+	Type     *Type
+	Optional bool
+}
+
+// Param 是函数参数，通过反射获取的函数没有参数名，使用 arg0、arg1 等
+type Param struct {
+	Name string
+	Type *Type
+}
+
+// Func 是一个绑定到页面的函数
+type Func struct {
+	// Name 是页面中的函数名，a.b 形式表示在 window.a 对象下
+	Name   string
+	Params []*Param
+	// Variadic 最后一个参数是否为可变参数
+	Variadic bool
+	// Result 是函数返回值，没有返回值时为 nil
+	Result *Type
+	// Stream 函数是否流式返回，在页面中返回异步迭代器
+	Stream bool
+}
+
+// Generator 收集函数和类型，生成 TypeScript 声明和 JS 模块
+type Generator struct {
+	Funcs []*Func
+	// Types 是函数中引用到的具名结构体类型
+	Types map[string]*Type
+	// typeNames 记录 Go 类型对应的类型名，用于处理重名和递归类型
+	typeNames map[string]string
+}
+
+// New 创建一个 Generator
+func New() *Generator {
+	return &Generator{
+		Types:     map[string]*Type{},
+		typeNames: map[string]string{},
+	}
+}
+
+// typeName 给 Go 类型分配一个不重复的类型名，key 是 Go 类型的唯一标识，
+// 返回的 bool 表示是否是第一次分配，第一次分配时调用方需要填充 Types
+func (g *Generator) typeName(key, pkg, name string) (string, bool) {
+	if n, ok := g.typeNames[key]; ok {
+		return n, false
+	}
+	name = identifier(name)
+	n := name
+	if _, ok := g.Types[n]; ok {
+		n = exportName(pkg) + name
+	}
+	for i := 2; ; i++ {
+		if _, ok := g.Types[n]; !ok {
+			break
+		}
+		n = exportName(pkg) + name + itoa(i)
+	}
+	g.typeNames[key] = n
+	// 先占位，避免递归类型重复分配
+	g.Types[n] = &Type{Kind: Object}
+	return n, true
+}
+
+func (g *Generator) sortedFuncs() []*Func {
+	funcs := append([]*Func{}, g.Funcs...)
+	sort.SliceStable(funcs, func(i, j int) bool { return funcs[i].Name < funcs[j].Name })
+	return funcs
+}
+
+func (g *Generator) sortedTypes() []string {
+	names := make([]string, 0, len(g.Types))
+	for n := range g.Types {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func exportName(s string) string {
+	if s == "" {
+		return s
+	}
+	s = s[strings.LastIndex(s, "/")+1:]
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// identifier 去掉类型名中不能作为标识符的字符，如泛型类型的 [ ]
+func identifier(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+func itoa(i int) string {
+	if i < 10 {
+		return string(rune('0' + i))
+	}
+	return itoa(i/10) + string(rune('0'+i%10))
+}
@@ -0,0 +1,95 @@
+package bindgen
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eyasliu/desktop/go-webview2"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+type User struct {
+	ID       int               `json:"id"`
+	Name     string            `json:"name"`
+	Email    string            `json:"email,omitempty"`
+	Tags     []string          `json:"tags"`
+	Meta     map[string]string `json:"meta"`
+	Manager  *User             `json:"manager"`
+	Created  time.Time         `json:"created"`
+	Password string            `json:"-"`
+	internal bool
+}
+
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+func testFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"ping":             func() {},
+		"add":              func(a, b int) int { return a + b },
+		"sum":              func(nums ...float64) float64 { return 0 },
+		"user.get":         func(ctx context.Context, id int) (*User, error) { return nil, nil },
+		"user.list":        func(filter map[string]interface{}) ([]User, error) { return nil, nil },
+		"user.save":        func(u User) error { return nil },
+		"files.watch":      func(ctx context.Context, dir string) (<-chan string, error) { return nil, nil },
+		"files.copy":       func(ctx context.Context, em webview2.Emitter, src, dst string) error { return nil },
+		"files.progress":   func(em webview2.Emitter) (Progress, error) { return Progress{}, nil },
+		"data.raw":         func(b []byte) []byte { return b },
+		"my-plugin.enable": func(on bool) {},
+	}
+}
+
+func TestGeneratorGolden(t *testing.T) {
+	g := New()
+	if err := g.AddFuncs(testFuncs()); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "bindings.d.ts", g.DTS())
+	golden(t, "bindings.js", g.JS())
+}
+
+func TestGeneratorDeterministic(t *testing.T) {
+	a, b := New(), New()
+	if err := a.AddFuncs(testFuncs()); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddFuncs(testFuncs()); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a.DTS(), b.DTS()) || !bytes.Equal(a.JS(), b.JS()) {
+		t.Error("output differs between runs")
+	}
+}
+
+func TestAddFuncNotFunction(t *testing.T) {
+	if err := New().AddFunc("x", 1); err == nil {
+		t.Error("AddFunc accepted a non-function value")
+	}
+}
+
+// golden 比较 got 和 testdata 中的文件，使用 -update 运行时更新文件
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s mismatch, run go test -update to regenerate\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
@@ -0,0 +1,231 @@
+package bindgen
+
+import (
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// bindDirective 用于在函数注释中指定页面中的函数名，如 //desktop:bind fs.readFile，
+// 指定为 - 时忽略该函数
+const bindDirective = "//desktop:bind"
+
+const webview2Path = "github.com/eyasliu/desktop/go-webview2"
+
+// AddPackage 解析 dir 目录下的 Go 包，把包中导出的函数添加为绑定函数，
+// 函数名默认为 Go 函数名，可以在函数注释中用 //desktop:bind name 指定。
+// 包中有无法解析的类型（如依赖没有下载）时不会报错，这些类型生成为 any
+func (g *Generator) AddPackage(dir string) error {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return err
+	}
+	fset := token.NewFileSet()
+	files := []*ast.File{}
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	info := &types.Info{Defs: map[*ast.Ident]types.Object{}}
+	path := bp.ImportPath
+	if path == "" || path == "." {
+		path = bp.Name
+	}
+	conf.Check(path, fset, files, info)
+
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv != nil || !fd.Name.IsExported() {
+				continue
+			}
+			name := bindName(fd)
+			if name == "-" {
+				continue
+			}
+			obj, ok := info.Defs[fd.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			g.addSignature(name, obj.Type().(*types.Signature))
+		}
+	}
+	return nil
+}
+
+func bindName(fd *ast.FuncDecl) string {
+	if fd.Doc != nil {
+		for _, c := range fd.Doc.List {
+			if strings.HasPrefix(c.Text, bindDirective+" ") {
+				return strings.TrimSpace(strings.TrimPrefix(c.Text, bindDirective))
+			}
+		}
+	}
+	return fd.Name.Name
+}
+
+func (g *Generator) addSignature(name string, sig *types.Signature) {
+	f := &Func{Name: name, Variadic: sig.Variadic()}
+	params := sig.Params()
+	for i := 0; i < params.Len(); i++ {
+		p := params.At(i)
+		t := p.Type()
+		if (i == 0 && isNamed(t, "context", "Context")) || isNamed(t, webview2Path, "Emitter") {
+			if isNamed(t, webview2Path, "Emitter") {
+				f.Stream = true
+			}
+			continue
+		}
+		if f.Variadic && i == params.Len()-1 {
+			t = t.(*types.Slice).Elem()
+		}
+		pname := p.Name()
+		if pname == "" || pname == "_" {
+			pname = "arg" + itoa(len(f.Params))
+		}
+		f.Params = append(f.Params, &Param{Name: pname, Type: g.goType(t)})
+	}
+	results := sig.Results()
+	if results.Len() > 0 && !types.Identical(results.At(0).Type(), types.Universe.Lookup("error").Type()) {
+		out := results.At(0).Type()
+		if ch, ok := out.Underlying().(*types.Chan); ok && ch.Dir() != types.SendOnly {
+			f.Stream = true
+			out = ch.Elem()
+		}
+		f.Result = g.goType(out)
+	} else if f.Stream {
+		f.Result = &Type{Kind: Any}
+	}
+	g.Funcs = append(g.Funcs, f)
+}
+
+func isNamed(t types.Type, pkg, name string) bool {
+	n, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := n.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkg && obj.Name() == name
+}
+
+// goType 把 go/types 中的类型转换为 Type，规则和 reflectType 一致
+func (g *Generator) goType(t types.Type) *Type {
+	if n, ok := t.(*types.Named); ok {
+		switch {
+		case isNamed(n, "time", "Time"):
+			return &Type{Kind: String}
+		case isNamed(n, "encoding/json", "RawMessage"):
+			return &Type{Kind: Any}
+		case hasMethod(n, "MarshalJSON"):
+			return &Type{Kind: Any}
+		}
+		if st, ok := n.Underlying().(*types.Struct); ok {
+			obj := n.Obj()
+			pkg := ""
+			if obj.Pkg() != nil {
+				pkg = obj.Pkg().Path()
+			}
+			short := types.TypeString(n, func(*types.Package) string { return "" })
+			name, first := g.typeName(types.TypeString(n, nil), pkg, short)
+			if first {
+				*g.Types[name] = Type{Kind: Object, Fields: g.goFields(st)}
+			}
+			return &Type{Kind: Ref, Name: name}
+		}
+		return g.goType(n.Underlying())
+	}
+	switch t := t.(type) {
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return &Type{Kind: Boolean}
+		case t.Info()&types.IsNumeric != 0:
+			return &Type{Kind: Number}
+		case t.Info()&types.IsString != 0:
+			return &Type{Kind: String}
+		}
+	case *types.Pointer:
+		e := *g.goType(t.Elem())
+		e.Nullable = true
+		return &e
+	case *types.Slice:
+		if isByte(t.Elem()) {
+			return &Type{Kind: String}
+		}
+		return &Type{Kind: Array, Elem: g.goType(t.Elem()), Nullable: true}
+	case *types.Array:
+		if isByte(t.Elem()) {
+			return &Type{Kind: String}
+		}
+		return &Type{Kind: Array, Elem: g.goType(t.Elem())}
+	case *types.Map:
+		return &Type{Kind: Record, Elem: g.goType(t.Elem()), Nullable: true}
+	case *types.Struct:
+		return &Type{Kind: Object, Fields: g.goFields(t)}
+	}
+	return &Type{Kind: Any}
+}
+
+func (g *Generator) goFields(st *types.Struct) []*Field {
+	fields := []*Field{}
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i)).Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := parseTag(tag)
+		ft := v.Type()
+		if v.Embedded() {
+			et := ft
+			if p, ok := et.(*types.Pointer); ok {
+				et = p.Elem()
+			}
+			est, isStruct := et.Underlying().(*types.Struct)
+			if !v.Exported() && !isStruct {
+				continue
+			}
+			if name == "" && isStruct {
+				fields = append(fields, g.goFields(est)...)
+				continue
+			}
+		} else if !v.Exported() {
+			continue
+		}
+		if name == "" {
+			name = v.Name()
+		}
+		f := &Field{Name: name, Optional: opts.contains("omitempty")}
+		if opts.contains("string") {
+			f.Type = &Type{Kind: String}
+		} else {
+			f.Type = g.goType(ft)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func isByte(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && (b.Kind() == types.Byte || b.Kind() == types.Uint8)
+}
+
+func hasMethod(t types.Type, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(t, false, nil, name)
+	_, ok := obj.(*types.Func)
+	return ok
+}
@@ -0,0 +1,177 @@
+package bindgen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eyasliu/desktop/go-webview2"
+)
+
+var (
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+	contextType   = reflect.TypeOf((*context.Context)(nil)).Elem()
+	emitterType   = reflect.TypeOf((*webview2.Emitter)(nil)).Elem()
+	timeType      = reflect.TypeOf(time.Time{})
+	rawType       = reflect.TypeOf(json.RawMessage{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+var qualifier = regexp.MustCompile(`[\w./-]*\.`)
+
+// AddFuncs 添加多个已绑定的函数，key 为页面中的函数名，通常来自 WebView.Bindings()
+func (g *Generator) AddFuncs(funcs map[string]interface{}) error {
+	names := make([]string, 0, len(funcs))
+	for name := range funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := g.AddFunc(name, funcs[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddFunc 通过反射添加一个绑定的函数，name 为页面中的函数名
+func (g *Generator) AddFunc(name string, fn interface{}) error {
+	t := reflect.TypeOf(fn)
+	if t == nil || t.Kind() != reflect.Func {
+		return errors.New("bindgen: " + name + " is not a function")
+	}
+	f := &Func{Name: name, Variadic: t.IsVariadic()}
+	for i := 0; i < t.NumIn(); i++ {
+		in := t.In(i)
+		if (i == 0 && in == contextType) || in == emitterType {
+			if in == emitterType {
+				f.Stream = true
+			}
+			continue
+		}
+		if f.Variadic && i == t.NumIn()-1 {
+			in = in.Elem()
+		}
+		f.Params = append(f.Params, &Param{Name: "arg" + strconv.Itoa(len(f.Params)), Type: g.reflectType(in)})
+	}
+	if t.NumOut() > 0 && t.Out(0) != errorType {
+		out := t.Out(0)
+		if out.Kind() == reflect.Chan && out.ChanDir()&reflect.RecvDir != 0 {
+			f.Stream = true
+			out = out.Elem()
+		}
+		f.Result = g.reflectType(out)
+	} else if f.Stream {
+		f.Result = &Type{Kind: Any}
+	}
+	g.Funcs = append(g.Funcs, f)
+	return nil
+}
+
+// reflectType 把 Go 类型转换为 Type，规则和 encoding/json 一致
+func (g *Generator) reflectType(t reflect.Type) *Type {
+	switch {
+	case t == timeType:
+		return &Type{Kind: String}
+	case t == rawType:
+		return &Type{Kind: Any}
+	case t.Implements(marshalerType) && t.Kind() != reflect.Ptr:
+		return &Type{Kind: Any}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Type{Kind: Boolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return &Type{Kind: Number}
+	case reflect.String:
+		return &Type{Kind: String}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte 会被编码为 base64 字符串
+			return &Type{Kind: String}
+		}
+		return &Type{Kind: Array, Elem: g.reflectType(t.Elem()), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &Type{Kind: Record, Elem: g.reflectType(t.Elem()), Nullable: true}
+	case reflect.Ptr:
+		e := *g.reflectType(t.Elem())
+		e.Nullable = true
+		return &e
+	case reflect.Struct:
+		if t.Name() == "" {
+			return &Type{Kind: Object, Fields: g.reflectFields(t)}
+		}
+		// 泛型类型的 Name 包含完整的类型参数，如 Page[github.com/a/b.User]，去掉包路径
+		name, first := g.typeName(t.PkgPath()+"."+t.Name(), t.PkgPath(), qualifier.ReplaceAllString(t.Name(), ""))
+		if first {
+			*g.Types[name] = Type{Kind: Object, Fields: g.reflectFields(t)}
+		}
+		return &Type{Kind: Ref, Name: name}
+	}
+	return &Type{Kind: Any}
+}
+
+func (g *Generator) reflectFields(t reflect.Type) []*Field {
+	fields := []*Field{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := parseTag(tag)
+		ft := sf.Type
+		if sf.Anonymous {
+			// 和 encoding/json 一样，没有 json tag 的匿名结构体字段会展开
+			et := ft
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+			if !sf.IsExported() && et.Kind() != reflect.Struct {
+				continue
+			}
+			if name == "" && et.Kind() == reflect.Struct {
+				fields = append(fields, g.reflectFields(et)...)
+				continue
+			}
+		} else if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		f := &Field{Name: name, Optional: opts.contains("omitempty")}
+		if opts.contains("string") {
+			f.Type = &Type{Kind: String}
+		} else {
+			f.Type = g.reflectType(ft)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+type tagOptions []string
+
+func (o tagOptions) contains(name string) bool {
+	for _, v := range o {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTag 解析 json tag，返回字段名和选项
+func parseTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	return parts[0], tagOptions(parts[1:])
+}
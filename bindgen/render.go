@@ -0,0 +1,245 @@
+package bindgen
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const header = "// Code generated by desktop-bindgen. DO NOT EDIT.\n"
+
+// node 是按 . 分隔的函数名组成的树，a.b 对应页面中的 window.a.b
+type node struct {
+	name     string
+	path     []string
+	fn       *Func
+	children []*node
+}
+
+func (g *Generator) tree() *node {
+	root := &node{}
+	for _, f := range g.sortedFuncs() {
+		n := root
+		path := strings.Split(f.Name, ".")
+		for i, seg := range path {
+			var next *node
+			for _, c := range n.children {
+				if c.name == seg {
+					next = c
+					break
+				}
+			}
+			if next == nil {
+				next = &node{name: seg, path: path[:i+1]}
+				n.children = append(n.children, next)
+			}
+			n = next
+		}
+		n.fn = f
+	}
+	return root
+}
+
+// DTS 生成 TypeScript 声明，包括结构体对应的 interface、JS 模块导出的函数，
+// 以及 window 上的全局函数
+func (g *Generator) DTS() []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString(header)
+	for _, name := range g.sortedTypes() {
+		buf.WriteString("\nexport interface " + name + " ")
+		buf.WriteString(g.tsObject(g.Types[name].Fields, ""))
+		buf.WriteString("\n")
+	}
+	root := g.tree()
+	if len(root.children) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, n := range root.children {
+		if !isIdentifier(n.name) {
+			continue
+		}
+		if len(n.children) == 0 {
+			buf.WriteString("export declare function " + n.name + g.tsSignature(n.fn, "") + ";\n")
+		} else {
+			buf.WriteString("export declare const " + n.name + ": " + g.tsNode(n, "") + ";\n")
+		}
+	}
+	buf.WriteString("\ndeclare global {\n\tinterface Window {\n")
+	for _, n := range root.children {
+		if isIdentifier(n.name) {
+			buf.WriteString("\t\t" + n.name + ": typeof " + n.name + ";\n")
+		} else {
+			buf.WriteString("\t\t" + propName(n.name) + ": " + g.tsNode(n, "\t\t") + ";\n")
+		}
+	}
+	buf.WriteString("\t}\n}\n")
+	return buf.Bytes()
+}
+
+// JS 生成 ES 模块，导出的函数调用 window 上绑定的同名函数，
+// 和 DTS 生成的声明文件放在一起（如 bindings.js 和 bindings.d.ts）即可获得类型提示
+func (g *Generator) JS() []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString(header)
+	buf.WriteString(`
+const bind = (path) => function () {
+	const fn = path.reduce((o, k) => o[k], window);
+	return fn.apply(this, arguments);
+};
+`)
+	root := g.tree()
+	if len(root.children) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, n := range root.children {
+		if !isIdentifier(n.name) {
+			continue
+		}
+		buf.WriteString("export const " + n.name + " = " + jsNode(n, "") + ";\n")
+	}
+	return buf.Bytes()
+}
+
+func jsNode(n *node, indent string) string {
+	fn := ""
+	if n.fn != nil {
+		path := make([]string, len(n.path))
+		for i, p := range n.path {
+			path[i] = strconv.Quote(p)
+		}
+		fn = "bind([" + strings.Join(path, ", ") + "])"
+		if len(n.children) == 0 {
+			return fn
+		}
+	}
+	obj := "{\n"
+	for _, c := range n.children {
+		obj += indent + "\t" + propName(c.name) + ": " + jsNode(c, indent+"\t") + ",\n"
+	}
+	obj += indent + "}"
+	if fn != "" {
+		return "Object.assign(" + fn + ", " + obj + ")"
+	}
+	return obj
+}
+
+// tsNode 生成命名空间对应的对象类型，既是函数又有子函数时生成带调用签名的对象类型
+func (g *Generator) tsNode(n *node, indent string) string {
+	if len(n.children) == 0 {
+		return g.tsFuncType(n.fn)
+	}
+	buf := "{\n"
+	if n.fn != nil {
+		buf += indent + "\t" + g.tsSignature(n.fn, indent+"\t") + ";\n"
+	}
+	for _, c := range n.children {
+		if len(c.children) == 0 {
+			buf += indent + "\t" + propName(c.name) + g.tsSignature(c.fn, indent+"\t") + ";\n"
+		} else {
+			buf += indent + "\t" + propName(c.name) + ": " + g.tsNode(c, indent+"\t") + ";\n"
+		}
+	}
+	return buf + indent + "}"
+}
+
+func (g *Generator) tsFuncType(f *Func) string {
+	sig := g.tsSignature(f, "")
+	i := strings.LastIndex(sig, "): ")
+	return sig[:i+1] + " => " + sig[i+3:]
+}
+
+// tsSignature 生成函数签名，所有绑定函数都可以在最后传入 AbortSignal 取消调用，
+// 可变参数的函数由于 TypeScript 的限制不声明 signal
+func (g *Generator) tsSignature(f *Func, indent string) string {
+	params := []string{}
+	for i, p := range f.Params {
+		if f.Variadic && i == len(f.Params)-1 {
+			params = append(params, "..."+p.Name+": "+g.tsArray(p.Type, indent))
+		} else {
+			params = append(params, p.Name+": "+g.ts(p.Type, indent))
+		}
+	}
+	if !f.Variadic {
+		params = append(params, "signal?: AbortSignal")
+	}
+	result := "void"
+	if f.Result != nil {
+		result = g.ts(f.Result, indent)
+	}
+	if f.Stream {
+		result = "AsyncIterableIterator<" + result + ">"
+	} else {
+		result = "Promise<" + result + ">"
+	}
+	return "(" + strings.Join(params, ", ") + "): " + result
+}
+
+func (g *Generator) ts(t *Type, indent string) string {
+	var s string
+	switch t.Kind {
+	case Boolean:
+		s = "boolean"
+	case Number:
+		s = "number"
+	case String:
+		s = "string"
+	case Array:
+		s = g.tsArray(t.Elem, indent)
+	case Record:
+		s = "Record<string, " + g.ts(t.Elem, indent) + ">"
+	case Object:
+		s = g.tsObject(t.Fields, indent)
+	case Ref:
+		s = t.Name
+	default:
+		return "any"
+	}
+	if t.Nullable {
+		s += " | null"
+	}
+	return s
+}
+
+func (g *Generator) tsArray(elem *Type, indent string) string {
+	s := g.ts(elem, indent)
+	if strings.Contains(s, " | ") {
+		s = "(" + s + ")"
+	}
+	return s + "[]"
+}
+
+func (g *Generator) tsObject(fields []*Field, indent string) string {
+	if len(fields) == 0 {
+		return "{}"
+	}
+	buf := "{\n"
+	for _, f := range fields {
+		opt := ""
+		if f.Optional {
+			opt = "?"
+		}
+		buf += indent + "\t" + propName(f.Name) + opt + ": " + g.ts(f.Type, indent+"\t") + ";\n"
+	}
+	return buf + indent + "}"
+}
+
+func propName(s string) string {
+	if isIdentifier(s) {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r == '_' || r == '$' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+			continue
+		}
+		return false
+	}
+	return true
+}
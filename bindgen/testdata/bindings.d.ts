@@ -0,0 +1,47 @@
+// Code generated by desktop-bindgen. DO NOT EDIT.
+
+export interface Progress {
+	done: number;
+	total: number;
+}
+
+export interface User {
+	id: number;
+	name: string;
+	email?: string;
+	tags: string[] | null;
+	meta: Record<string, string> | null;
+	manager: User | null;
+	created: string;
+}
+
+export declare function add(arg0: number, arg1: number, signal?: AbortSignal): Promise<number>;
+export declare const data: {
+	raw(arg0: string, signal?: AbortSignal): Promise<string>;
+};
+export declare const files: {
+	copy(arg0: string, arg1: string, signal?: AbortSignal): AsyncIterableIterator<any>;
+	progress(signal?: AbortSignal): AsyncIterableIterator<Progress>;
+	watch(arg0: string, signal?: AbortSignal): AsyncIterableIterator<string>;
+};
+export declare function ping(signal?: AbortSignal): Promise<void>;
+export declare function sum(...arg0: number[]): Promise<number>;
+export declare const user: {
+	get(arg0: number, signal?: AbortSignal): Promise<User | null>;
+	list(arg0: Record<string, any> | null, signal?: AbortSignal): Promise<User[] | null>;
+	save(arg0: User, signal?: AbortSignal): Promise<void>;
+};
+
+declare global {
+	interface Window {
+		add: typeof add;
+		data: typeof data;
+		files: typeof files;
+		"my-plugin": {
+			enable(arg0: boolean, signal?: AbortSignal): Promise<void>;
+		};
+		ping: typeof ping;
+		sum: typeof sum;
+		user: typeof user;
+	}
+}
@@ -0,0 +1,23 @@
+// Code generated by desktop-bindgen. DO NOT EDIT.
+
+const bind = (path) => function () {
+	const fn = path.reduce((o, k) => o[k], window);
+	return fn.apply(this, arguments);
+};
+
+export const add = bind(["add"]);
+export const data = {
+	raw: bind(["data", "raw"]),
+};
+export const files = {
+	copy: bind(["files", "copy"]),
+	progress: bind(["files", "progress"]),
+	watch: bind(["files", "watch"]),
+};
+export const ping = bind(["ping"]);
+export const sum = bind(["sum"]);
+export const user = {
+	get: bind(["user", "get"]),
+	list: bind(["user", "list"]),
+	save: bind(["user", "save"]),
+};
@@ -0,0 +1,41 @@
+// desktop-bindgen 根据 Go 包中导出的函数生成 TypeScript 类型声明和 JS 调用模块
+//
+//	desktop-bindgen -dir ./api -o ./web/src/bindings
+//
+// 生成 bindings.d.ts 和 bindings.js，包中的函数需要以相同的名字绑定到页面，
+// 可以在函数注释中用 //desktop:bind name 指定页面中的函数名，指定为 - 时忽略该函数
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/eyasliu/desktop/bindgen"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "Go package directory")
+	out := flag.String("o", "bindings", "output path without extension, writes <o>.d.ts and <o>.js")
+	flag.Parse()
+
+	if err := run(*dir, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "desktop-bindgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, out string) error {
+	g := bindgen.New()
+	if err := g.AddPackage(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(out+".d.ts", g.DTS(), 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(out+".js", g.JS(), 0644)
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/eyasliu/desktop/go-webview2"
 )
 
-// New 新建一个 webview 窗口
+// New 新建一个 webview 窗口，创建失败（如无法安装 WebView2 Runtime）时记录日志并返回 nil
 func New(opt *Options) WebView {
 	// 托盘图标
 	iconpath := opt.GetIcon()
@@ -25,30 +25,36 @@ func New(opt *Options) WebView {
 		}
 	}
 
-	wvOpts := webview2.WebViewOptions{
-		Debug:             opt.Debug,
-		StartURL:          opt.StartURL,
-		FallbackPage:      opt.FallbackPage,
-		DataPath:          opt.DataPath,
-		AutoFocus:         opt.AutoFocus,
-		HideWindowOnClose: opt.HideWindowOnClose,
-		Logger:            opt.Logger,
-		WindowOptions: webview2.WindowOptions{
-			Icon:      iconpath,
-			Frameless: opt.Frameless,
-			Title:     opt.Title,
-			Center:    opt.Center,
-			Width:     uint(opt.Width),
-			Height:    uint(opt.Height),
-		},
-	}
-	if wvOpts.Logger == nil {
-		wvOpts.Logger = &defaultLogger{}
-	}
+	wvOpts := windowOptions(opt, iconpath)
 
 	if IsSupportTray() && opt.Tray != nil {
 		go tray.Run(opt.Tray)
 	}
-	w := webview2.NewWin(wvOpts, opt.Tray)
+	w, err := webview2.NewWinE(wvOpts, opt.Tray)
+	if err != nil {
+		wvOpts.Logger.Info("create window failed:", err)
+		if IsSupportTray() && opt.Tray != nil {
+			tray.Quit()
+		}
+		return nil
+	}
 	return w
 }
+
+// NewApp 新建一个在当前线程运行的 App，通过 App.New 创建的窗口都在这个线程中运行，
+// Run 必须和 NewApp 在同一个 goroutine 执行
+func NewApp(opt *AppOptions) *App {
+	return &App{webview2.NewApp(appOptions(opt))}
+}
+
+// appWindowOptions 返回 App.New 创建窗口使用的配置
+func appWindowOptions(opt *Options) webview2.WebViewOptions {
+	return windowOptions(opt, opt.GetIcon())
+}
+
+// windowOptions 把 Options 转换为 webview2 的配置，iconpath 为窗口图标
+func windowOptions(opt *Options, iconpath string) webview2.WebViewOptions {
+	wvOpts := webviewOptions(opt)
+	wvOpts.WindowOptions.Icon = iconpath
+	return wvOpts
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+// +build !windows
+
+package desktop
+
+import "github.com/eyasliu/desktop/go-webview2"
+
+// New 新建一个 webview 窗口，非 windows 系统下没有可用的 webview，
+// 返回的是 NewHeadless 创建的无界面实现
+func New(opt *Options) WebView {
+	return NewHeadless(opt)
+}
+
+// NewApp 新建一个 App，非 windows 系统下返回的是 NewHeadlessApp 创建的无界面实现
+func NewApp(opt *AppOptions) *App {
+	return NewHeadlessApp(opt)
+}
+
+// appWindowOptions 返回 App.New 创建窗口使用的配置
+func appWindowOptions(opt *Options) webview2.WebViewOptions {
+	return webviewOptions(opt)
+}
@@ -0,0 +1,293 @@
+package webview2
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// AppOptions 是 App 的配置
+type AppOptions struct {
+	// KeepAlive 为 true 时最后一个窗口关闭后 Run 不返回，需要调用 Quit 退出，
+	// 用于只有托盘图标或者之后还会再打开窗口的应用
+	KeepAlive bool
+
+	Logger logger
+}
+
+// AppWindow 是 App 管理的窗口，windows 下为 *Window，NewHeadlessApp 创建的 App 中为 *Headless
+type AppWindow interface {
+	Dispatch(f func())
+	Destroy()
+	SetTitle(title string)
+	SetSize(width int, height int, hint Hint)
+	Navigate(url string)
+	SetHtml(html string)
+	Eval(js string)
+	Show()
+	Hide()
+	Emit(event string, payload interface{}) error
+	On(event string, fn func(payload json.RawMessage)) func()
+}
+
+// appHost 是 App 在各平台的实现，负责在 UI 线程中创建窗口和运行消息循环
+type appHost interface {
+	// create 在 UI 线程中创建窗口，closed 在窗口销毁后调用，
+	// open 用于页面通过 window.open 在新的桌面窗口中打开页面
+	create(opts WebViewOptions, closed func(), open func(WebViewOptions)) (AppWindow, error)
+	// destroy 在 UI 线程中销毁窗口，不受 HideWindowOnClose 影响
+	destroy(w AppWindow)
+	// dispatch 把 f 放到 UI 线程执行
+	dispatch(f func())
+	// run 运行消息循环，直到调用了 stop
+	run()
+	stop()
+}
+
+// appWindow 是 App 中的一个窗口
+type appWindow struct {
+	id     string
+	window AppWindow
+}
+
+// App 在同一个 UI 线程中管理多个窗口，每个窗口有唯一的 id，可以按 id 查找窗口，
+// 或者通过 Broadcast 向所有窗口发送事件。默认最后一个窗口关闭后 Run 返回
+type App struct {
+	opts AppOptions
+	host appHost
+
+	m       sync.Mutex
+	windows []*appWindow
+	nextID  int
+	closed  []*appListener
+	// quitting 表示已经调用了 Quit，之后不能再创建窗口
+	quitting bool
+}
+
+// appListener 是通过 OnWindowClosed 注册的监听
+type appListener struct {
+	fn func(id string)
+}
+
+func newApp(opts AppOptions, host appHost) *App {
+	if opts.Logger == nil {
+		opts.Logger = discardLogger{}
+	}
+	return &App{opts: opts, host: host}
+}
+
+// NewHeadlessApp 创建一个管理 Headless 窗口的 App，可以在任意系统上运行，主要用于单元测试
+func NewHeadlessApp(opts AppOptions) *App {
+	return newApp(opts, &headlessAppHost{quit: make(chan struct{})})
+}
+
+// NewWindow 在 UI 线程中创建 id 为 id 的窗口并返回，id 为空时自动生成，
+// id 已经存在时返回错误。窗口的 HideWindowOnClose 生效时关闭窗口只会隐藏，不会从 App 中移除
+func (a *App) NewWindow(id string, opts WebViewOptions) (AppWindow, error) {
+	a.m.Lock()
+	if a.quitting {
+		a.m.Unlock()
+		return nil, errors.New("app is quitting")
+	}
+	if id == "" {
+		for id == "" || a.find(id) != nil {
+			a.nextID++
+			id = "window-" + strconv.Itoa(a.nextID)
+		}
+	}
+	if a.find(id) != nil {
+		a.m.Unlock()
+		return nil, errors.New("window " + id + " already exists")
+	}
+	// 先占住 id，创建窗口时会执行消息循环，其他窗口可能在这期间使用同样的 id
+	aw := &appWindow{id: id}
+	a.windows = append(a.windows, aw)
+	a.m.Unlock()
+
+	if opts.Logger == nil {
+		opts.Logger = a.opts.Logger
+	}
+	opts.id = id
+	w, err := a.host.create(opts, func() { a.windowClosed(aw) }, a.openWindow)
+	a.m.Lock()
+	defer a.m.Unlock()
+	if err != nil {
+		a.remove(aw)
+		return nil, err
+	}
+	aw.window = w
+	return w, nil
+}
+
+// find 返回 id 对应的窗口，需要持有锁
+func (a *App) find(id string) *appWindow {
+	for _, w := range a.windows {
+		if w.id == id {
+			return w
+		}
+	}
+	return nil
+}
+
+// remove 从 App 中移除窗口，返回窗口是否还在 App 中，需要持有锁
+func (a *App) remove(aw *appWindow) bool {
+	for i, w := range a.windows {
+		if w == aw {
+			a.windows = append(a.windows[:i:i], a.windows[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// windowClosed 在窗口销毁后从 App 中移除，最后一个窗口关闭并且没有设置 KeepAlive 时退出
+func (a *App) windowClosed(aw *appWindow) {
+	a.m.Lock()
+	if !a.remove(aw) {
+		a.m.Unlock()
+		return
+	}
+	last := len(a.windows) == 0
+	listeners := append([]*appListener{}, a.closed...)
+	a.m.Unlock()
+
+	for _, l := range listeners {
+		l.fn(aw.id)
+	}
+	if last && !a.opts.KeepAlive {
+		a.Quit()
+	}
+}
+
+// openWindow 在 App 中创建新窗口，用于 NavigationPolicy.NewWindow 为 NewWindowDesktop 时打开的页面
+func (a *App) openWindow(opts WebViewOptions) {
+	a.host.dispatch(func() {
+		if _, err := a.NewWindow("", opts); err != nil {
+			a.opts.Logger.Info("open window failed:", err)
+		}
+	})
+}
+
+// Window 返回 id 对应的窗口，没有时返回 nil, false
+func (a *App) Window(id string) (AppWindow, bool) {
+	a.m.Lock()
+	defer a.m.Unlock()
+	if w := a.find(id); w != nil && w.window != nil {
+		return w.window, true
+	}
+	return nil, false
+}
+
+// IDs 按创建顺序返回所有窗口的 id
+func (a *App) IDs() []string {
+	a.m.Lock()
+	defer a.m.Unlock()
+	ids := make([]string, 0, len(a.windows))
+	for _, w := range a.windows {
+		if w.window != nil {
+			ids = append(ids, w.id)
+		}
+	}
+	return ids
+}
+
+// Windows 按创建顺序返回所有窗口
+func (a *App) Windows() []AppWindow {
+	a.m.Lock()
+	defer a.m.Unlock()
+	list := make([]AppWindow, 0, len(a.windows))
+	for _, w := range a.windows {
+		if w.window != nil {
+			list = append(list, w.window)
+		}
+	}
+	return list
+}
+
+// Broadcast 向所有窗口的页面发送事件，页面中通过 window.desktop.on(event, cb) 监听，
+// 某个窗口发送失败时继续发送其他窗口，返回第一个错误
+func (a *App) Broadcast(event string, payload interface{}) error {
+	var first error
+	for _, w := range a.Windows() {
+		if err := w.Emit(event, payload); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// OnWindowClosed 监听窗口关闭，fn 的参数为窗口的 id，返回取消监听的函数，
+// fn 在 UI 线程中执行，不能阻塞
+func (a *App) OnWindowClosed(fn func(id string)) func() {
+	l := &appListener{fn}
+	a.m.Lock()
+	a.closed = append(a.closed, l)
+	a.m.Unlock()
+	return func() {
+		a.m.Lock()
+		defer a.m.Unlock()
+		for i, v := range a.closed {
+			if v == l {
+				a.closed = append(a.closed[:i:i], a.closed[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Dispatch 把 f 放到 App 的 UI 线程执行
+func (a *App) Dispatch(f func()) {
+	a.host.dispatch(f)
+}
+
+// Run 运行 App 的消息循环，直到调用了 Quit，或者最后一个窗口关闭并且没有设置 KeepAlive
+func (a *App) Run() {
+	a.host.run()
+}
+
+// Quit 销毁所有窗口并退出 Run，可以在任意 goroutine 中调用
+func (a *App) Quit() {
+	a.m.Lock()
+	if a.quitting {
+		a.m.Unlock()
+		return
+	}
+	a.quitting = true
+	a.m.Unlock()
+	a.host.dispatch(func() {
+		for _, w := range a.Windows() {
+			a.host.destroy(w)
+		}
+		a.host.stop()
+	})
+}
+
+// headlessAppHost 是 NewHeadlessApp 的实现，Headless 没有 UI 线程，所有操作都立即执行
+type headlessAppHost struct {
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+func (h *headlessAppHost) create(opts WebViewOptions, closed func(), open func(WebViewOptions)) (AppWindow, error) {
+	w := NewHeadless(opts)
+	w.closed = closed
+	w.openWindow = open
+	return w, nil
+}
+
+func (h *headlessAppHost) destroy(w AppWindow) {
+	w.Destroy()
+}
+
+func (h *headlessAppHost) dispatch(f func()) {
+	f()
+}
+
+func (h *headlessAppHost) run() {
+	<-h.quit
+}
+
+func (h *headlessAppHost) stop() {
+	h.quitOnce.Do(func() { close(h.quit) })
+}
@@ -0,0 +1,159 @@
+package webview2
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newAppWindow(t *testing.T, a *App, id string, opts WebViewOptions) *Headless {
+	t.Helper()
+	w, err := a.NewWindow(id, opts)
+	if err != nil {
+		t.Fatalf("NewWindow(%q) = %v", id, err)
+	}
+	return w.(*Headless)
+}
+
+// running 在新的 goroutine 中运行 App，返回的 channel 在 Run 返回后关闭
+func running(a *App) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		a.Run()
+		close(done)
+	}()
+	return done
+}
+
+func TestAppWindowIDs(t *testing.T) {
+	a := NewHeadlessApp(AppOptions{KeepAlive: true})
+	defer a.Quit()
+	main := newAppWindow(t, a, "main", WebViewOptions{})
+	first := newAppWindow(t, a, "", WebViewOptions{})
+	newAppWindow(t, a, "window-2", WebViewOptions{})
+	second := newAppWindow(t, a, "", WebViewOptions{})
+
+	if _, err := a.NewWindow("main", WebViewOptions{}); err == nil {
+		t.Error("NewWindow accepted a duplicate id")
+	}
+	// 自动生成的 id 跳过已经使用的 id
+	if got, want := a.IDs(), []string{"main", "window-1", "window-2", "window-3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IDs() = %v, want %v", got, want)
+	}
+	if got := a.Windows(); len(got) != 4 || got[0] != AppWindow(main) || got[1] != AppWindow(first) || got[3] != AppWindow(second) {
+		t.Errorf("Windows() = %v, not in creation order", got)
+	}
+	if w, ok := a.Window("window-1"); !ok || w != AppWindow(first) {
+		t.Errorf("Window(window-1) = %v, %v", w, ok)
+	}
+	if _, ok := a.Window("missing"); ok {
+		t.Error("Window(missing) found a window")
+	}
+
+	// 关闭后 id 可以重新使用
+	main.Destroy()
+	if _, ok := a.Window("main"); ok {
+		t.Error("closed window is still in the App")
+	}
+	newAppWindow(t, a, "main", WebViewOptions{})
+	if got, want := a.IDs(), []string{"window-1", "window-2", "window-3", "main"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IDs() = %v, want %v", got, want)
+	}
+}
+
+func TestAppWindowClosed(t *testing.T) {
+	a := NewHeadlessApp(AppOptions{KeepAlive: true})
+	defer a.Quit()
+	var closed []string
+	off := a.OnWindowClosed(func(id string) { closed = append(closed, id) })
+	main := newAppWindow(t, a, "main", WebViewOptions{})
+	settings := newAppWindow(t, a, "settings", WebViewOptions{})
+	hidden := newAppWindow(t, a, "hidden", WebViewOptions{HideWindowOnClose: true})
+
+	settings.RequestClose()
+	hidden.RequestClose()
+	main.Destroy()
+	main.Destroy()
+	if got, want := closed, []string{"settings", "main"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("closed = %v, want %v", got, want)
+	}
+	if got := a.IDs(); !reflect.DeepEqual(got, []string{"hidden"}) {
+		t.Errorf("IDs() = %v, want the hidden window", got)
+	}
+
+	off()
+	hidden.Destroy()
+	if len(closed) != 2 {
+		t.Errorf("listener called after off: %v", closed)
+	}
+}
+
+func TestAppQuitOnLastClose(t *testing.T) {
+	a := NewHeadlessApp(AppOptions{})
+	main := newAppWindow(t, a, "main", WebViewOptions{})
+	settings := newAppWindow(t, a, "settings", WebViewOptions{})
+	done := running(a)
+
+	settings.Destroy()
+	select {
+	case <-done:
+		t.Fatal("Run returned before the last window closed")
+	case <-time.After(10 * time.Millisecond):
+	}
+	main.Destroy()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the last window closed")
+	}
+	if _, err := a.NewWindow("", WebViewOptions{}); err == nil {
+		t.Error("NewWindow succeeded after the App quit")
+	}
+}
+
+func TestAppKeepAlive(t *testing.T) {
+	a := NewHeadlessApp(AppOptions{KeepAlive: true})
+	main := newAppWindow(t, a, "main", WebViewOptions{})
+	done := running(a)
+
+	main.Destroy()
+	select {
+	case <-done:
+		t.Fatal("Run returned with KeepAlive")
+	case <-time.After(10 * time.Millisecond):
+	}
+	// 没有窗口时仍然可以打开新窗口
+	w := newAppWindow(t, a, "", WebViewOptions{})
+
+	a.Quit()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after Quit")
+	}
+	select {
+	case <-w.done:
+	default:
+		t.Error("Quit did not destroy the windows")
+	}
+	if _, err := a.NewWindow("", WebViewOptions{}); err == nil {
+		t.Error("NewWindow succeeded after Quit")
+	}
+}
+
+func TestAppOpenWindow(t *testing.T) {
+	a := NewHeadlessApp(AppOptions{KeepAlive: true})
+	defer a.Quit()
+	main := newAppWindow(t, a, "main", WebViewOptions{
+		NavigationPolicy: &NavigationPolicy{NewWindow: NewWindowDesktop},
+	})
+	main.OpenWindow("https://example.com/popup")
+	ids := a.IDs()
+	if len(ids) != 2 {
+		t.Fatalf("IDs() = %v, want the popup window", ids)
+	}
+	w, _ := a.Window(ids[1])
+	if got := w.(*Headless).URL(); got != "https://example.com/popup" {
+		t.Errorf("popup URL = %q", got)
+	}
+}
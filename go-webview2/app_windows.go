@@ -0,0 +1,140 @@
+//go:build windows
+// +build windows
+
+package webview2
+
+import (
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"github.com/eyasliu/desktop/go-webview2/internal/w32"
+
+	"golang.org/x/sys/windows"
+)
+
+var registerAppClass sync.Once
+
+// NewApp 创建在当前线程中运行的 App，所有窗口都在这个线程中创建和运行，
+// 当前 goroutine 会固定在当前线程，Run 必须在调用 NewApp 的 goroutine 中执行。
+// 在其他 goroutine 中调用 NewWindow 时会等待 UI 线程创建好窗口，所以需要先执行 Run
+func NewApp(opts AppOptions) *App {
+	runtime.LockOSThread()
+	h := &winAppHost{}
+	h.thread, _, _ = w32.Kernel32GetCurrentThreadID.Call()
+	h.createMessageWindow()
+	return newApp(opts, h)
+}
+
+// winAppHost 是 windows 下 App 的实现，通过一个 message-only 窗口把函数放到 UI 线程执行，
+// 发给窗口而不是线程的消息在创建 webview 的消息循环中也能正常处理
+type winAppHost struct {
+	thread uintptr
+	hwnd   uintptr
+	m      sync.Mutex
+	tasks  []func()
+}
+
+func (h *winAppHost) createMessageWindow() {
+	var hinstance windows.Handle
+	_ = windows.GetModuleHandleEx(0, nil, &hinstance)
+	className, _ := windows.UTF16PtrFromString("webview_app")
+	registerAppClass.Do(func() {
+		wc := w32.WndClassExW{
+			CbSize:        uint32(unsafe.Sizeof(w32.WndClassExW{})),
+			HInstance:     hinstance,
+			LpszClassName: className,
+			LpfnWndProc:   windows.NewCallback(appWndProc),
+		}
+		_, _, _ = w32.User32RegisterClassExW.Call(uintptr(unsafe.Pointer(&wc)))
+	})
+	h.hwnd, _, _ = w32.User32CreateWindowExW.Call(
+		0,
+		uintptr(unsafe.Pointer(className)),
+		0,
+		0,
+		0,
+		0,
+		0,
+		0,
+		w32.HWNDMessage,
+		0,
+		uintptr(hinstance),
+		0,
+	)
+	setWindowContext(h.hwnd, h)
+}
+
+func appWndProc(hwnd, msg, wp, lp uintptr) uintptr {
+	if h, ok := getWindowContext(hwnd).(*winAppHost); ok && msg == w32.WMApp {
+		h.runTasks()
+		return 0
+	}
+	r, _, _ := w32.User32DefWindowProcW.Call(hwnd, msg, wp, lp)
+	return r
+}
+
+// runTasks 依次执行 dispatch 的函数，函数中创建窗口时会嵌套执行消息循环，
+// 所以每次只取一个，嵌套调用时由内层继续执行剩下的函数
+func (h *winAppHost) runTasks() {
+	for {
+		h.m.Lock()
+		if len(h.tasks) == 0 {
+			h.m.Unlock()
+			return
+		}
+		f := h.tasks[0]
+		h.tasks = h.tasks[1:]
+		h.m.Unlock()
+		f()
+	}
+}
+
+// onThread 返回当前 goroutine 是否在 UI 线程中执行
+func (h *winAppHost) onThread() bool {
+	id, _, _ := w32.Kernel32GetCurrentThreadID.Call()
+	return id == h.thread
+}
+
+func (h *winAppHost) create(opts WebViewOptions, closed func(), open func(WebViewOptions)) (AppWindow, error) {
+	if !h.onThread() {
+		var (
+			w    AppWindow
+			err  error
+			done = make(chan struct{})
+		)
+		h.dispatch(func() {
+			w, err = h.create(opts, closed, open)
+			close(done)
+		})
+		<-done
+		return w, err
+	}
+	win, err := newWindow(opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	win.webview.destroyed = closed
+	win.webview.openWindow = open
+	return win, nil
+}
+
+func (h *winAppHost) destroy(w AppWindow) {
+	_, _, _ = w32.User32DestroyWindow.Call(w.(*Window).webview.hwnd)
+}
+
+func (h *winAppHost) dispatch(f func()) {
+	h.m.Lock()
+	h.tasks = append(h.tasks, f)
+	h.m.Unlock()
+	_, _, _ = w32.User32PostMessageW.Call(h.hwnd, w32.WMApp, 0, 0)
+}
+
+func (h *winAppHost) run() {
+	messageLoop(nil)
+	runtime.UnlockOSThread()
+}
+
+func (h *winAppHost) stop() {
+	_, _, _ = w32.User32PostQuitMessage.Call(0)
+}
@@ -0,0 +1,342 @@
+package webview2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// BindOption 是 Bind 的可选配置
+type BindOption func(o *bindOptions)
+
+type bindOptions struct {
+	timeout  time.Duration
+	nameCase NameCase
+	exclude  []string
+	origins  []string
+}
+
+// BindTimeout 设置绑定函数的最长执行时间，超时后 JS 调用会 reject，
+// 函数的 context.Context 参数也会被取消
+func BindTimeout(d time.Duration) BindOption {
+	return func(o *bindOptions) {
+		o.timeout = d
+	}
+}
+
+// NameCase 是 BindObject 生成的 JS 方法名的命名方式
+type NameCase int
+
+const (
+	// LowerCamelCase 首字母小写，如 ReadFile 为 readFile，URLPath 为 urlPath，是默认值
+	LowerCamelCase NameCase = iota
+	// OriginalCase 和 Go 方法名一致
+	OriginalCase
+	// SnakeCase 下划线分隔，如 ReadFile 为 read_file
+	SnakeCase
+)
+
+// BindCase 设置 BindObject 生成的 JS 方法名的命名方式
+func BindCase(c NameCase) BindOption {
+	return func(o *bindOptions) {
+		o.nameCase = c
+	}
+}
+
+// BindExclude 设置 BindObject 不需要绑定的方法，可以是 Go 方法名或者 JS 方法名
+func BindExclude(methods ...string) BindOption {
+	return func(o *bindOptions) {
+		o.exclude = append(o.exclude, methods...)
+	}
+}
+
+// binding 是一个绑定到页面的 Go 函数
+type binding struct {
+	fn reflect.Value
+	// withContext 函数第一个参数是否为 context.Context
+	withContext bool
+	// withEmitter 函数在 context.Context 之后（如果有）的参数是否为 Emitter
+	withEmitter bool
+	// returnsChan 函数第一个返回值是否为可读的 channel
+	returnsChan bool
+	// stream 函数是否流式返回多个值
+	stream  bool
+	timeout time.Duration
+	// origins 是允许调用的页面地址，为 nil 时使用 bridge 的默认配置
+	origins []string
+	// script 是在页面中注入 JS 函数的脚本
+	script ScriptID
+}
+
+func newBinding(f interface{}, opts []BindOption) (*binding, error) {
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Func {
+		return nil, errors.New("only functions can be bound")
+	}
+	if n := v.Type().NumOut(); n > 2 || n == 2 && !v.Type().Out(1).Implements(errorType) {
+		return nil, errors.New("function may only return a value or a value+error")
+	}
+	o := &bindOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	t := v.Type()
+	bd := &binding{
+		fn:          v,
+		withContext: t.NumIn() > 0 && t.In(0) == contextType,
+		timeout:     o.timeout,
+		origins:     o.origins,
+	}
+	if i := bd.fixedArgs(); t.NumIn() > i && t.In(i) == emitterType {
+		bd.withEmitter = true
+	}
+	if t.NumOut() > 0 && t.Out(0).Kind() == reflect.Chan && t.Out(0).ChanDir()&reflect.RecvDir != 0 {
+		bd.returnsChan = true
+	}
+	if bd.withEmitter && bd.returnsChan {
+		return nil, errors.New("function may either take an Emitter or return a channel")
+	}
+	bd.stream = bd.withEmitter || bd.returnsChan
+	return bd, nil
+}
+
+// fixedArgs 返回不需要 JS 传入的参数个数，即 context.Context 和 Emitter 参数
+func (bd *binding) fixedArgs() int {
+	n := 0
+	if bd.withContext {
+		n++
+	}
+	if bd.withEmitter {
+		n++
+	}
+	return n
+}
+
+// call 解析参数并调用函数，函数第一个参数为 context.Context 时传入 ctx，
+// 有 Emitter 参数时传入 em
+func (bd *binding) call(ctx context.Context, d *RPCMessage, em Emitter) (interface{}, error) {
+	params, err := d.ParamList()
+	if err != nil {
+		return nil, err
+	}
+
+	v := bd.fn
+	isVariadic := v.Type().IsVariadic()
+	numIn := v.Type().NumIn()
+	args := []reflect.Value{}
+	if bd.withContext {
+		args = append(args, reflect.ValueOf(ctx))
+	}
+	if bd.withEmitter {
+		args = append(args, reflect.ValueOf(&em).Elem())
+	}
+	first := bd.fixedArgs()
+	if (isVariadic && len(params) < numIn-first-1) || (!isVariadic && len(params) != numIn-first) {
+		return nil, &RPCError{Code: RPCInvalidParams, Message: "function arguments mismatch"}
+	}
+	for i := range params {
+		var arg reflect.Value
+		if isVariadic && i+first >= numIn-1 {
+			arg = reflect.New(v.Type().In(numIn - 1).Elem())
+		} else {
+			arg = reflect.New(v.Type().In(i + first))
+		}
+		if err := json.Unmarshal(params[i], arg.Interface()); err != nil {
+			return nil, &RPCError{Code: RPCInvalidParams, Message: err.Error()}
+		}
+		args = append(args, arg.Elem())
+	}
+
+	res := v.Call(args)
+	switch len(res) {
+	case 0:
+		// No results from the function, just return nil
+		return nil, nil
+
+	case 1:
+		// One result may be a value, or an error
+		if res[0].Type().Implements(errorType) {
+			if res[0].Interface() != nil {
+				return nil, res[0].Interface().(error)
+			}
+			return nil, nil
+		}
+		return res[0].Interface(), nil
+
+	case 2:
+		// Two results: first one is value, second is error
+		if !res[1].Type().Implements(errorType) {
+			return nil, errors.New("second return value must be an error")
+		}
+		if res[1].Interface() == nil {
+			return res[0].Interface(), nil
+		}
+		return res[0].Interface(), res[1].Interface().(error)
+
+	default:
+		return nil, errors.New("unexpected number of return values")
+	}
+}
+
+// bind 注册 Go 函数，并在页面注入同名的 JS 函数，
+// JS 函数最后一个参数可以传入 AbortSignal 用于取消调用，
+// 流式返回的函数在 JS 中返回异步迭代器
+func (b *bridge) bind(name string, f interface{}, opts ...BindOption) error {
+	bd, err := newBinding(f, opts)
+	if err != nil {
+		return err
+	}
+	call := "call"
+	if bd.stream {
+		call = "stream"
+	}
+	// name 为 a.b 形式时挂在 window.a 对象下
+	bd.script = b.init("(function() { var name = " + jsString(name) + ";" + `
+		var path = name.split("."), o = window;
+		for (var i = 0; i < path.length - 1; i++) {
+		  o = o[path[i]] = o[path[i]] || {};
+		}
+		o[path[path.length - 1]] = function() {
+		  var a = window._rpc.args(arguments);
+		  return window._rpc.` + call + `(name, a.params, a.signal);
+		}
+	})()`)
+
+	b.m.Lock()
+	old, ok := b.bindings[name]
+	b.bindings[name] = bd
+	b.m.Unlock()
+	if ok {
+		b.removeScript(old.script)
+	}
+	return nil
+}
+
+// unbind 移除绑定的函数，name 为 BindObject 的 ns 时移除该对象下所有的函数，
+// 页面中的 JS 函数会被删除，已经在执行的调用不受影响
+func (b *bridge) unbind(name string) bool {
+	b.m.Lock()
+	removed := []*binding{}
+	for n, bd := range b.bindings {
+		if n == name || strings.HasPrefix(n, name+".") {
+			removed = append(removed, bd)
+			delete(b.bindings, n)
+		}
+	}
+	b.m.Unlock()
+	if len(removed) == 0 {
+		return false
+	}
+	for _, bd := range removed {
+		b.removeScript(bd.script)
+	}
+	b.browser.Eval("(function() { var path = " + jsString(name) + `.split("."), o = window;
+		for (var i = 0; i < path.length - 1; i++) {
+		  o = o[path[i]];
+		  if (!o) return;
+		}
+		delete o[path[path.length - 1]];
+	})()`)
+	return true
+}
+
+// bindObject 把 obj 导出的方法绑定为 ns.method
+func (b *bridge) bindObject(ns string, obj interface{}, opts ...BindOption) error {
+	methods, err := objectMethods(ns, obj, opts)
+	if err != nil {
+		return err
+	}
+	for name, fn := range methods {
+		if err := b.bind(name, fn, opts...); err != nil {
+			return errors.New(name + ": " + err.Error())
+		}
+	}
+	return nil
+}
+
+// objectMethods 返回 obj 导出的方法，key 为页面中的函数名 ns.method，
+// obj 为指针时包括指针接收者的方法
+func objectMethods(ns string, obj interface{}, opts []BindOption) (map[string]interface{}, error) {
+	v := reflect.ValueOf(obj)
+	if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return nil, errors.New("only non-nil objects can be bound")
+	}
+	o := &bindOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	excluded := map[string]bool{}
+	for _, name := range o.exclude {
+		excluded[name] = true
+	}
+	methods := map[string]interface{}{}
+	t := v.Type()
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		if !m.IsExported() {
+			continue
+		}
+		name := o.nameCase.format(m.Name)
+		if excluded[m.Name] || excluded[name] {
+			continue
+		}
+		methods[ns+"."+name] = v.Method(i).Interface()
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("object has no exported methods to bind")
+	}
+	return methods, nil
+}
+
+func (c NameCase) format(name string) string {
+	switch c {
+	case OriginalCase:
+		return name
+	case SnakeCase:
+		words := splitWords(name)
+		for i, w := range words {
+			words[i] = strings.ToLower(w)
+		}
+		return strings.Join(words, "_")
+	default:
+		words := splitWords(name)
+		words[0] = strings.ToLower(words[0])
+		return strings.Join(words, "")
+	}
+}
+
+// splitWords 按大小写拆分方法名，连续的大写字母作为一个单词，如 URLPath 为 URL、Path
+func splitWords(name string) []string {
+	r := []rune(name)
+	words := []string{}
+	start := 0
+	for i := 1; i < len(r); i++ {
+		lowerToUpper := unicode.IsLower(r[i-1]) && unicode.IsUpper(r[i])
+		acronymEnd := i+1 < len(r) && unicode.IsUpper(r[i-1]) && unicode.IsUpper(r[i]) && unicode.IsLower(r[i+1])
+		if lowerToUpper || acronymEnd {
+			words = append(words, string(r[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(r[start:]))
+}
+
+// funcs 返回已绑定的 Go 函数
+func (b *bridge) funcs() map[string]interface{} {
+	b.m.Lock()
+	defer b.m.Unlock()
+	m := make(map[string]interface{}, len(b.bindings))
+	for name, bd := range b.bindings {
+		m[name] = bd.fn.Interface()
+	}
+	return m
+}
@@ -0,0 +1,28 @@
+package webview2
+
+import "testing"
+
+func TestBindInvalid(t *testing.T) {
+	h := NewHeadless(WebViewOptions{})
+	tests := map[string]interface{}{
+		"not a function":   1,
+		"nil":              nil,
+		"too many results": func() (int, int, error) { return 0, 0, nil },
+		"second not error": func() (int, int) { return 0, 0 },
+		"emitter and chan": func(em Emitter) <-chan int { return nil },
+	}
+	for name, f := range tests {
+		if err := h.Bind(name, f); err == nil {
+			t.Errorf("Bind(%s) accepted %T", name, f)
+		}
+	}
+	if got := h.Bindings(); len(got) != 0 {
+		t.Errorf("Bindings() = %v, want no invalid bindings", got)
+	}
+	if err := h.Bind("ok", func() error { return nil }); err != nil {
+		t.Errorf("Bind(ok) = %v", err)
+	}
+	if _, ok := h.Bindings()["ok"]; !ok {
+		t.Error("Bindings() is missing a valid binding")
+	}
+}
@@ -0,0 +1,399 @@
+package webview2
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultEvalTimeout 是 EvalAsync 等待页面返回结果的默认超时时间
+const DefaultEvalTimeout = 30 * time.Second
+
+// browser 是 webview 使用的浏览器后端，windows 下由 edge.Chromium 实现，
+// 在没有系统 webview 的环境（如 Linux CI）中由纯内存的 memoryBrowser 实现
+type browser interface {
+	Embed(hwnd uintptr) bool
+	Resize()
+	// SetInset 设置 WebView 到窗口客户区边缘的距离，下次 Resize 时生效
+	SetInset(inset int)
+	Navigate(url string) error
+	NavigateToString(htmlContent string)
+	Init(script string)
+	// AddScript 和 Init 一样注入脚本，注入完成后通过 done 返回脚本 id，用于 RemoveScript
+	AddScript(script string, done func(id string))
+	RemoveScript(id string)
+	Eval(script string)
+	NotifyParentWindowPositionChanged() error
+	Focus()
+}
+
+func jsString(v interface{}) string { b, _ := json.Marshal(v); return string(b) }
+
+// bridge 负责 Go 函数绑定以及 JS 调用 Go 的消息处理，不依赖具体平台，
+// 由 webview 和 Headless 共用
+type bridge struct {
+	m        sync.Mutex
+	browser  browser
+	bindings map[string]*binding
+	// scripts 是通过 Init 注入的脚本在浏览器中的 id，还没注入完成时为空
+	scripts    map[ScriptID]string
+	nextScript ScriptID
+	// pending 是 Go 发给 JS 的请求中还在等待响应的部分
+	pending map[string]*goRequest
+	nextID  uint64
+	// page 是当前页面的地址，Go 发给 JS 的请求只接受同一个来源的页面的响应
+	page string
+	// calls 是 JS 调用 Go 还没执行完的函数，用于取消调用
+	calls map[string]*jsCall
+	// streams 是还在向 JS 流式发送数据的调用
+	streams map[string]*streamEmitter
+	// listeners 是监听页面事件的函数，events 是等待处理的页面事件
+	listeners []*eventListener
+	events    chan jsEvent
+	// ctx 在窗口销毁时取消，docCtx 在页面跳转时取消
+	ctx       context.Context
+	cancel    context.CancelFunc
+	docCtx    context.Context
+	docCancel context.CancelFunc
+	// dispatch 把函数放到 UI 线程执行
+	dispatch func(f func())
+	logger   logger
+	// origins 是默认允许调用绑定函数的页面地址，为 nil 时允许所有页面
+	origins []string
+	// retry 处理错误页面中的 window.desktop.retry()，没有配置错误页面时为 nil
+	retry func()
+	// closeWindow 处理页面中的 window.desktop.close(result)，result 为传入的值
+	closeWindow func(result json.RawMessage)
+	// frame 处理页面中按下标题栏或者最大化按钮，x、y 是按下的位置，单位为 css 像素，
+	// regions 记录 frameRuntime 计算的页面中的区域
+	frame   func(x, y float64)
+	regions func(regions []frameRegion)
+}
+
+func newBridge(wb browser, dispatch func(f func()), l logger) *bridge {
+	ctx, cancel := context.WithCancel(context.Background())
+	docCtx, docCancel := context.WithCancel(ctx)
+	b := &bridge{
+		browser:   wb,
+		bindings:  map[string]*binding{},
+		scripts:   map[ScriptID]string{},
+		pending:   map[string]*goRequest{},
+		calls:     map[string]*jsCall{},
+		streams:   map[string]*streamEmitter{},
+		events:    make(chan jsEvent, 256),
+		ctx:       ctx,
+		cancel:    cancel,
+		docCtx:    docCtx,
+		docCancel: docCancel,
+		dispatch:  dispatch,
+		logger:    l,
+	}
+	go b.eventLoop()
+	return b
+}
+
+// setup 注入页面的 RPC 运行时，必须在 browser 初始化之后、注入其他脚本之前调用
+func (b *bridge) setup() {
+	b.browser.Init(rpcRuntime)
+	b.browser.Init(eventRuntime)
+	b.browser.Init(fallbackRuntime)
+	b.browser.Init(modalRuntime)
+	b.browser.Init(windowRuntime)
+}
+
+// reset 在页面跳转后调用，取消上一个页面中还没执行完的 Go 函数
+func (b *bridge) reset() {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.docCancel()
+	b.docCtx, b.docCancel = context.WithCancel(b.ctx)
+}
+
+// setPage 在页面地址变化时调用
+func (b *bridge) setPage(url string) {
+	b.m.Lock()
+	b.page = url
+	b.m.Unlock()
+}
+
+// close 在窗口销毁时调用，取消所有还没执行完的 Go 函数，并停止处理页面事件
+func (b *bridge) close() {
+	b.cancel()
+}
+
+// msgcb 处理页面发来的消息，source 是发送消息的页面地址
+func (b *bridge) msgcb(msg, source string) {
+	d, err := DecodeRPCMessage([]byte(msg))
+	if err != nil {
+		b.logger.Info("invalid rpc message:", err)
+		if d != nil && len(d.ID) > 0 {
+			b.send(NewRPCResponse(d.ID, nil, err))
+		}
+		return
+	}
+	if d.IsResponse() {
+		b.response(d, source)
+		return
+	}
+
+	// 内部方法同样只允许 AllowedOrigins 中的页面和窗口自己打开的 about:blank 页面调用，
+	// $cancel 和 $pull 只能操作同一个页面发起的调用
+	switch d.Method {
+	case "$cancel", "$pull":
+	default:
+		if strings.HasPrefix(d.Method, "$") && source != "about:blank" && !allowOrigin(b.origins, source) {
+			b.logger.Info("rpc notification "+d.Method+" rejected, origin not allowed:", source)
+			return
+		}
+	}
+
+	switch d.Method {
+	case "$ready":
+		// 新页面的 RPC 运行时初始化完成
+		b.reset()
+		return
+	case "$cancel":
+		b.cancelCall(d, source)
+		return
+	case "$pull":
+		b.pullStream(d, source)
+		return
+	case "$event":
+		b.receiveEvent(d)
+		return
+	case "$retry":
+		if b.retry != nil {
+			b.retry()
+		}
+		return
+	case "$frame":
+		if b.frame != nil {
+			var x, y float64
+			if params, err := d.ParamList(); err == nil && len(params) > 1 {
+				_ = json.Unmarshal(params[0], &x)
+				_ = json.Unmarshal(params[1], &y)
+			}
+			b.frame(x, y)
+		}
+		return
+	case "$regions":
+		if b.regions != nil {
+			var regions []frameRegion
+			if params, err := d.ParamList(); err == nil && len(params) > 0 {
+				_ = json.Unmarshal(params[0], &regions)
+			}
+			b.regions(regions)
+		}
+		return
+	case "$close":
+		if b.closeWindow != nil {
+			var result json.RawMessage
+			if params, err := d.ParamList(); err == nil && len(params) > 0 {
+				result = params[0]
+			}
+			b.closeWindow(result)
+		}
+		return
+	}
+	b.invoke(d, source)
+}
+
+// invoke 在新的 goroutine 中执行 JS 调用的 Go 函数，执行完后回复结果，
+// 页面跳转、窗口销毁、JS 取消调用或者超时都会取消执行
+func (b *bridge) invoke(d *RPCMessage, source string) {
+	b.m.Lock()
+	bd, ok := b.bindings[d.Method]
+	ctx := b.docCtx
+	b.m.Unlock()
+	if !ok {
+		b.reply(d, nil, &RPCError{Code: RPCMethodNotFound, Message: "method not found: " + d.Method})
+		return
+	}
+	origins := bd.origins
+	if origins == nil {
+		origins = b.origins
+	}
+	if !allowOrigin(origins, source) {
+		b.logger.Info("rpc call "+d.Method+" rejected, origin not allowed:", source)
+		b.reply(d, nil, &RPCError{Code: RPCForbidden, Message: "origin not allowed to call " + d.Method + ": " + source})
+		return
+	}
+	if bd.stream && !d.IsRequest() {
+		b.reply(d, nil, &RPCError{Code: RPCInvalidRequest, Message: "stream function " + d.Method + " must be called with an id"})
+		return
+	}
+
+	var cancel context.CancelFunc
+	if bd.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, bd.timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	id := string(d.ID)
+	var em *streamEmitter
+	if d.IsRequest() {
+		b.m.Lock()
+		b.calls[id] = &jsCall{cancel: cancel, source: source}
+		if bd.stream {
+			em = newStreamEmitter(b, d.ID, ctx)
+			b.streams[id] = em
+		}
+		b.m.Unlock()
+	}
+
+	go func() {
+		defer func() {
+			cancel()
+			if d.IsRequest() {
+				b.m.Lock()
+				delete(b.calls, id)
+				delete(b.streams, id)
+				b.m.Unlock()
+			}
+		}()
+
+		type result struct {
+			v   interface{}
+			err error
+		}
+		done := make(chan result, 1)
+		go func() {
+			v, err := bd.call(ctx, d, em)
+			if err == nil && bd.returnsChan {
+				// 返回 channel 的函数，把 channel 中的值依次发给 JS
+				err = em.drain(reflect.ValueOf(v))
+				v = nil
+			}
+			done <- result{v, err}
+		}()
+		select {
+		case r := <-done:
+			b.reply(d, r.v, r.err)
+		case <-ctx.Done():
+			b.reply(d, nil, &RPCError{Code: RPCCanceled, Message: ctx.Err().Error()})
+		}
+	}()
+}
+
+// reply 回复 JS 的请求，通知不需要回复，只记录错误日志
+func (b *bridge) reply(d *RPCMessage, v interface{}, err error) {
+	if d.IsNotification() {
+		if err != nil {
+			b.logger.Info("rpc notification "+d.Method+" failed:", err)
+		}
+		return
+	}
+	b.send(NewRPCResponse(d.ID, v, err))
+}
+
+// cancelCall 处理 JS 取消调用的通知，参数为要取消的请求 id，只能取消 source 发起的调用
+func (b *bridge) cancelCall(d *RPCMessage, source string) {
+	params, err := d.ParamList()
+	if err != nil || len(params) == 0 {
+		return
+	}
+	if c := b.call(string(params[0]), source); c != nil {
+		c.cancel()
+	}
+}
+
+// jsCall 是 JS 调用 Go 还没执行完的函数，source 是发起调用的页面地址
+type jsCall struct {
+	cancel context.CancelFunc
+	source string
+}
+
+// call 返回 id 对应的还没执行完的调用，不是 source 发起的调用返回 nil
+func (b *bridge) call(id, source string) *jsCall {
+	b.m.Lock()
+	defer b.m.Unlock()
+	c, ok := b.calls[id]
+	if !ok || c.source != source {
+		return nil
+	}
+	return c
+}
+
+// send 把消息发送到页面的 RPC 运行时
+func (b *bridge) send(m *RPCMessage) {
+	data, err := EncodeRPCMessage(m)
+	if err != nil {
+		b.logger.Info("encode rpc message failed:", err)
+		return
+	}
+	b.dispatch(func() {
+		b.browser.Eval(rpcScript(data))
+	})
+}
+
+// goRequest 是 Go 发给 JS 还在等待响应的请求，source 是发送请求时的页面地址
+type goRequest struct {
+	ch     chan *RPCMessage
+	source string
+}
+
+// response 处理页面对 Go 请求的响应，不是和发送请求时同一个来源的页面的响应会被忽略
+func (b *bridge) response(d *RPCMessage, source string) {
+	b.m.Lock()
+	r, ok := b.pending[string(d.ID)]
+	if ok && !sameOrigin(r.source, source) {
+		b.m.Unlock()
+		b.logger.Info("rpc response "+string(d.ID)+" rejected, sent to "+r.source+" but received from:", source)
+		return
+	}
+	delete(b.pending, string(d.ID))
+	b.m.Unlock()
+	if ok {
+		r.ch <- d
+	}
+}
+
+// request 向页面发起请求并等待响应，响应错误时返回 *RPCError。
+// 响应需要经过 UI 线程处理，所以不能在 UI 线程中调用，否则会一直阻塞到 ctx 结束
+func (b *bridge) request(ctx context.Context, method string, params ...interface{}) (json.RawMessage, error) {
+	b.m.Lock()
+	b.nextID++
+	id := jsString("go-" + strconv.FormatUint(b.nextID, 10))
+	ch := make(chan *RPCMessage, 1)
+	b.pending[id] = &goRequest{ch: ch, source: b.page}
+	b.m.Unlock()
+
+	m, err := NewRPCRequest(json.RawMessage(id), method, params...)
+	if err != nil {
+		b.m.Lock()
+		delete(b.pending, id)
+		b.m.Unlock()
+		return nil, err
+	}
+	b.send(m)
+
+	select {
+	case res := <-ch:
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		return res.Result, nil
+	case <-ctx.Done():
+		b.m.Lock()
+		delete(b.pending, id)
+		b.m.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// evalAsync 在页面执行 js 并返回表达式的值，值为 Promise 时返回 resolve 的值
+func (b *bridge) evalAsync(ctx context.Context, js string) (json.RawMessage, error) {
+	return b.request(ctx, "$eval", js)
+}
+
+// callJS 调用页面的全局函数 fn，支持 a.b.c 形式的路径，返回值为 Promise 时返回 resolve 的值
+func (b *bridge) callJS(ctx context.Context, fn string, args ...interface{}) (json.RawMessage, error) {
+	if args == nil {
+		args = []interface{}{}
+	}
+	return b.request(ctx, "$call", fn, args)
+}
@@ -0,0 +1,369 @@
+package webview2
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheRule 是离线缓存的规则，地址匹配 Pattern 的 GET 请求会被缓存
+type CacheRule struct {
+	// Pattern 是请求地址，* 匹配任意多个字符，? 匹配一个字符，如 https://example.com/*
+	Pattern string
+	// MaxAge 是缓存的有效期，超过后不再使用，为 0 时一直有效
+	MaxAge time.Duration
+}
+
+// CacheOptions 是离线缓存的配置
+type CacheOptions struct {
+	// Dir 是缓存目录，为空时使用 DataPath 下的 OfflineCache 目录
+	Dir string
+	// Rules 是缓存规则，按顺序使用第一个匹配的规则，为空时不缓存任何请求
+	Rules []CacheRule
+	// MaxSize 是缓存的最大字节数，超过时删除最久没有使用的缓存，为 0 时不限制
+	MaxSize int64
+}
+
+// CacheStats 是离线缓存的统计信息
+type CacheStats struct {
+	// Entries 是缓存的响应数量
+	Entries int
+	// Size 是缓存的响应体的总字节数
+	Size int64
+	// Hits 是离线时使用缓存的次数
+	Hits int64
+	// Misses 是离线时没有可用缓存的次数
+	Misses int64
+}
+
+// cacheEntry 是缓存的一个响应，和响应体一起保存在缓存目录中
+type cacheEntry struct {
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Stored time.Time   `json:"stored"`
+	Size   int64       `json:"size"`
+
+	used time.Time
+}
+
+// cacheHeader 标记由离线缓存返回的响应，浏览器收到这些响应时不会再次保存
+const cacheHeader = "X-Offline-Cache"
+
+// offlineDuration 是跳转因为网络错误失败后使用缓存的时间，之后重新尝试网络
+const offlineDuration = 30 * time.Second
+
+// OfflineCache 把匹配规则的 GET 请求的响应缓存到本地目录。页面和资源由浏览器正常加载，
+// 带着浏览器的 Cookie 和 HTTP 缓存，浏览器收到响应后再保存到缓存中。
+// 跳转因为网络错误（如断网）失败时进入离线状态，重新打开有缓存的页面，之后的 30 秒内匹配规则的请求使用缓存返回，
+// 浏览器再次收到网络响应时退出离线状态。页面没有缓存时才显示 FallbackPage
+type OfflineCache struct {
+	opts    CacheOptions
+	m       sync.Mutex
+	entries map[string]*cacheEntry
+	size    int64
+	hits    int64
+	misses  int64
+	// offlineUntil 是离线状态结束的时间，为零值时在线
+	offlineUntil time.Time
+}
+
+// NewOfflineCache 创建离线缓存，读取缓存目录中已有的缓存
+func NewOfflineCache(opts CacheOptions) (*OfflineCache, error) {
+	if opts.Dir == "" {
+		return nil, errors.New("cache dir is empty")
+	}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, err
+	}
+	c := &OfflineCache{opts: opts, entries: map[string]*cacheEntry{}}
+	files, err := filepath.Glob(filepath.Join(opts.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		e := &cacheEntry{}
+		if err := json.Unmarshal(b, e); err != nil || e.URL == "" {
+			continue
+		}
+		e.used = e.Stored
+		c.entries[e.URL] = e
+		c.size += e.Size
+	}
+	c.m.Lock()
+	c.evict()
+	c.m.Unlock()
+	return c, nil
+}
+
+// defaultCacheDir 返回 dataPath 下的缓存目录，dataPath 为空时使用系统的缓存目录
+func defaultCacheDir(dataPath string) string {
+	return filepath.Join(defaultDataPath(dataPath), "OfflineCache")
+}
+
+// defaultDataPath 返回保存离线缓存和窗口状态的目录，dataPath 为空时使用系统缓存目录下以程序名命名的目录
+func defaultDataPath(dataPath string) string {
+	if dataPath == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			dir = os.TempDir()
+		}
+		exe, _ := os.Executable()
+		dataPath = filepath.Join(dir, filepath.Base(exe))
+	}
+	return dataPath
+}
+
+// newCache 根据 WebViewOptions.Cache 创建离线缓存，没有配置时返回 nil
+func newCache(options WebViewOptions) (*OfflineCache, error) {
+	if options.Cache == nil {
+		return nil, nil
+	}
+	opts := *options.Cache
+	if opts.Dir == "" {
+		opts.Dir = defaultCacheDir(options.DataPath)
+	}
+	return NewOfflineCache(opts)
+}
+
+// rule 返回 uri 匹配的缓存规则
+func (c *OfflineCache) rule(uri string) (CacheRule, bool) {
+	for _, rule := range c.opts.Rules {
+		if matchURIPattern(rule.Pattern, uri) {
+			return rule, true
+		}
+	}
+	return CacheRule{}, false
+}
+
+// patterns 返回需要拦截的请求地址，即所有缓存规则的 Pattern
+func (c *OfflineCache) patterns() []string {
+	patterns := make([]string, 0, len(c.opts.Rules))
+	for _, rule := range c.opts.Rules {
+		patterns = append(patterns, rule.Pattern)
+	}
+	return patterns
+}
+
+// Middleware 实现 ResourceMiddleware，其他中间件和 Handle 没有处理的请求才会使用缓存，
+// 在线时请求交给浏览器处理，离线时使用没有过期的缓存返回
+func (c *OfflineCache) Middleware(next ResourceHandler) ResourceHandler {
+	return func(r *ResourceRequest) Action {
+		a := next(r)
+		if a.kind != actionAllow || r.Method != http.MethodGet ||
+			r.Context == ResourceWebSocket || r.Context == ResourceEventSource || !c.offline() {
+			return a
+		}
+		uri := r.URL.String()
+		rule, ok := c.rule(uri)
+		if !ok {
+			return a
+		}
+		if cached := c.load(uri, rule.MaxAge); cached != nil {
+			return Respond(cached)
+		}
+		// 没有缓存时交给浏览器处理，跳转失败时会显示 FallbackPage
+		return a
+	}
+}
+
+// received 在浏览器收到响应后调用，退出离线状态，返回响应是否需要保存，
+// 离线缓存返回的响应不是来自网络，不会退出离线状态也不需要保存
+func (c *OfflineCache) received(method, uri string, status int, header http.Header) bool {
+	if header.Get(cacheHeader) != "" {
+		return false
+	}
+	c.m.Lock()
+	c.offlineUntil = time.Time{}
+	c.m.Unlock()
+	if method != http.MethodGet || status < 200 || status >= 300 {
+		return false
+	}
+	_, ok := c.rule(uri)
+	return ok
+}
+
+// offline 返回是否处于离线状态
+func (c *OfflineCache) offline() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return time.Now().Before(c.offlineUntil)
+}
+
+// networkError 判断跳转失败的原因是否是网络不可用
+func networkError(e *NavigationCompletedEvent) bool {
+	if e.Success || e.HTTPStatus != 0 {
+		return false
+	}
+	switch e.WebErrorStatus {
+	case WebErrorServerUnreachable, WebErrorTimeout, WebErrorConnectionAborted, WebErrorConnectionReset,
+		WebErrorDisconnected, WebErrorCannotConnect, WebErrorHostNameNotResolved:
+		return true
+	}
+	return false
+}
+
+// recoverable 返回失败的跳转是否可以使用缓存重新打开，FallbackPage 不需要显示
+func (c *OfflineCache) recoverable(e *NavigationCompletedEvent) bool {
+	if !networkError(e) {
+		return false
+	}
+	rule, ok := c.rule(e.URL)
+	if !ok {
+		return false
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	entry, ok := c.entries[e.URL]
+	return ok && (rule.MaxAge <= 0 || time.Since(entry.Stored) <= rule.MaxAge)
+}
+
+// listen 监听跳转完成的事件，跳转因为网络错误失败时进入离线状态，有缓存时通过 reload 重新打开页面
+func (c *OfflineCache) listen(n *navigationEvents, reload func(url string)) {
+	n.OnNavigationCompleted(func(e *NavigationCompletedEvent) {
+		if !networkError(e) {
+			return
+		}
+		c.m.Lock()
+		c.offlineUntil = time.Now().Add(offlineDuration)
+		c.m.Unlock()
+		if c.recoverable(e) {
+			reload(e.URL)
+		}
+	})
+}
+
+func (c *OfflineCache) file(uri, ext string) string {
+	sum := sha256.Sum256([]byte(uri))
+	return filepath.Join(c.opts.Dir, hex.EncodeToString(sum[:])+ext)
+}
+
+// store 保存浏览器收到的响应，超过 MaxSize 的响应不会保存。
+// 浏览器给出的响应体已经解压，所以不保存 Content-Encoding 和长度相关的响应头
+func (c *OfflineCache) store(uri string, status int, header http.Header, body []byte) {
+	size := int64(len(body))
+	if c.opts.MaxSize > 0 && size > c.opts.MaxSize {
+		return
+	}
+	header = header.Clone()
+	for _, k := range []string{"Content-Encoding", "Content-Length", "Transfer-Encoding", "Connection"} {
+		header.Del(k)
+	}
+	now := time.Now()
+	e := &cacheEntry{URL: uri, Status: status, Header: header, Stored: now, Size: size, used: now}
+	meta, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	if err := os.WriteFile(c.file(uri, ".body"), body, 0644); err != nil {
+		return
+	}
+	if err := os.WriteFile(c.file(uri, ".json"), meta, 0644); err != nil {
+		return
+	}
+	if old, ok := c.entries[uri]; ok {
+		c.size -= old.Size
+	}
+	c.entries[uri] = e
+	c.size += size
+	c.evict()
+}
+
+// load 读取没有过期的缓存，没有可用的缓存时返回 nil
+func (c *OfflineCache) load(uri string, maxAge time.Duration) *resourceResponse {
+	c.m.Lock()
+	defer c.m.Unlock()
+	e, ok := c.entries[uri]
+	if ok && maxAge > 0 && time.Since(e.Stored) > maxAge {
+		c.removeEntry(e)
+		ok = false
+	}
+	if !ok {
+		c.misses++
+		return nil
+	}
+	body, err := os.ReadFile(c.file(uri, ".body"))
+	if err != nil {
+		c.removeEntry(e)
+		c.misses++
+		return nil
+	}
+	e.used = time.Now()
+	c.hits++
+	res := newResourceResponse()
+	for k, v := range e.Header {
+		res.header[k] = v
+	}
+	res.header.Set(cacheHeader, "hit")
+	res.WriteHeader(e.Status)
+	res.body.Write(body)
+	return res
+}
+
+// evict 删除最久没有使用的缓存直到不超过 MaxSize，需要持有锁
+func (c *OfflineCache) evict() {
+	if c.opts.MaxSize <= 0 || c.size <= c.opts.MaxSize {
+		return
+	}
+	entries := make([]*cacheEntry, 0, len(c.entries))
+	for _, e := range c.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].used.Before(entries[j].used) })
+	for _, e := range entries {
+		if c.size <= c.opts.MaxSize {
+			break
+		}
+		c.removeEntry(e)
+	}
+}
+
+// removeEntry 删除一个缓存，需要持有锁
+func (c *OfflineCache) removeEntry(e *cacheEntry) {
+	_ = os.Remove(c.file(e.URL, ".json"))
+	_ = os.Remove(c.file(e.URL, ".body"))
+	delete(c.entries, e.URL)
+	c.size -= e.Size
+}
+
+// Stats 返回缓存的统计信息
+func (c *OfflineCache) Stats() CacheStats {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return CacheStats{Entries: len(c.entries), Size: c.size, Hits: c.hits, Misses: c.misses}
+}
+
+// Clear 删除所有缓存，统计的次数也会清零
+func (c *OfflineCache) Clear() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	var errs []string
+	for _, e := range c.entries {
+		for _, ext := range []string{".json", ".body"} {
+			if err := os.Remove(c.file(e.URL, ext)); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+	c.entries = map[string]*cacheEntry{}
+	c.size, c.hits, c.misses = 0, 0, 0
+	if len(errs) > 0 {
+		return errors.New("clear cache: " + strings.Join(errs, "; "))
+	}
+	return nil
+}
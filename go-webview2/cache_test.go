@@ -0,0 +1,233 @@
+package webview2
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCache(t *testing.T, opts CacheOptions) *OfflineCache {
+	t.Helper()
+	if opts.Dir == "" {
+		opts.Dir = t.TempDir()
+	}
+	c, err := NewOfflineCache(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func cachedBody(t *testing.T, c *OfflineCache, uri string, maxAge time.Duration) (string, bool) {
+	t.Helper()
+	res := c.load(uri, maxAge)
+	if res == nil {
+		return "", false
+	}
+	if res.Header().Get(cacheHeader) == "" {
+		t.Errorf("%s: cached response without %s", uri, cacheHeader)
+	}
+	return res.body.String(), true
+}
+
+func TestOfflineCacheStoreLoad(t *testing.T) {
+	dir := t.TempDir()
+	c := newTestCache(t, CacheOptions{Dir: dir, Rules: []CacheRule{{Pattern: "https://app.example.com/*"}}})
+	header := http.Header{"Content-Type": {"text/html"}, "Content-Encoding": {"gzip"}, "Content-Length": {"4"}}
+	c.store("https://app.example.com/", 200, header, []byte("page"))
+	c.store("https://app.example.com/app.js", 200, http.Header{}, []byte("js"))
+
+	body, ok := cachedBody(t, c, "https://app.example.com/", 0)
+	if !ok || body != "page" {
+		t.Fatalf("load = %q, %v", body, ok)
+	}
+	res := c.load("https://app.example.com/", 0)
+	if res.Header().Get("Content-Type") != "text/html" || res.Header().Get("Content-Encoding") != "" || res.status != 200 {
+		t.Errorf("cached header = %v, status = %d", res.Header(), res.status)
+	}
+	if _, ok := cachedBody(t, c, "https://app.example.com/missing", 0); ok {
+		t.Error("load returned a response that was not stored")
+	}
+	if got, want := c.Stats(), (CacheStats{Entries: 2, Size: 6, Hits: 2, Misses: 1}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	// 重新打开时读取目录中已有的缓存
+	c2 := newTestCache(t, CacheOptions{Dir: dir})
+	if body, ok := cachedBody(t, c2, "https://app.example.com/app.js", 0); !ok || body != "js" {
+		t.Errorf("reopened load = %q, %v", body, ok)
+	}
+
+	if err := c2.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c2.Stats(); got != (CacheStats{}) {
+		t.Errorf("Stats() after Clear = %+v", got)
+	}
+	if c3 := newTestCache(t, CacheOptions{Dir: dir}); c3.Stats().Entries != 0 {
+		t.Errorf("entries left in the cache dir after Clear: %+v", c3.Stats())
+	}
+}
+
+func TestOfflineCacheMaxAge(t *testing.T) {
+	c := newTestCache(t, CacheOptions{})
+	c.store("https://example.com/", 200, http.Header{}, []byte("old"))
+	c.m.Lock()
+	c.entries["https://example.com/"].Stored = time.Now().Add(-2 * time.Hour)
+	c.m.Unlock()
+
+	if _, ok := cachedBody(t, c, "https://example.com/", 3*time.Hour); !ok {
+		t.Error("cache expired before MaxAge")
+	}
+	if _, ok := cachedBody(t, c, "https://example.com/", time.Hour); ok {
+		t.Error("cache was used after MaxAge")
+	}
+	if got := c.Stats().Entries; got != 0 {
+		t.Errorf("expired entry was not removed, Entries = %d", got)
+	}
+}
+
+func TestOfflineCacheEvict(t *testing.T) {
+	c := newTestCache(t, CacheOptions{MaxSize: 10})
+	c.store("https://example.com/a", 200, http.Header{}, []byte("aaaa"))
+	c.store("https://example.com/b", 200, http.Header{}, []byte("bbbb"))
+	// 使用 a 之后 b 是最久没有使用的缓存
+	cachedBody(t, c, "https://example.com/a", 0)
+	c.store("https://example.com/c", 200, http.Header{}, []byte("cccc"))
+
+	for uri, want := range map[string]bool{
+		"https://example.com/a": true,
+		"https://example.com/b": false,
+		"https://example.com/c": true,
+	} {
+		if _, ok := cachedBody(t, c, uri, 0); ok != want {
+			t.Errorf("%s cached = %v, want %v", uri, ok, want)
+		}
+	}
+	if got := c.Stats().Size; got != 8 {
+		t.Errorf("Size = %d, want 8", got)
+	}
+
+	// 超过 MaxSize 的响应不保存
+	c.store("https://example.com/large", 200, http.Header{}, []byte("0123456789a"))
+	if _, ok := cachedBody(t, c, "https://example.com/large", 0); ok {
+		t.Error("response larger than MaxSize was stored")
+	}
+}
+
+func TestOfflineCacheRecoverable(t *testing.T) {
+	c := newTestCache(t, CacheOptions{Rules: []CacheRule{
+		{Pattern: "https://app.example.com/*", MaxAge: time.Hour},
+	}})
+	c.store("https://app.example.com/", 200, http.Header{}, []byte("page"))
+
+	tests := []struct {
+		name string
+		e    NavigationCompletedEvent
+		want bool
+	}{
+		{"network error", NavigationCompletedEvent{URL: "https://app.example.com/", WebErrorStatus: WebErrorDisconnected}, true},
+		{"dns error", NavigationCompletedEvent{URL: "https://app.example.com/", WebErrorStatus: WebErrorHostNameNotResolved}, true},
+		{"http error", NavigationCompletedEvent{URL: "https://app.example.com/", WebErrorStatus: WebErrorDisconnected, HTTPStatus: 500}, false},
+		{"not a network error", NavigationCompletedEvent{URL: "https://app.example.com/", WebErrorStatus: WebErrorCertificateExpired}, false},
+		{"not cached", NavigationCompletedEvent{URL: "https://app.example.com/other", WebErrorStatus: WebErrorDisconnected}, false},
+		{"no rule", NavigationCompletedEvent{URL: "https://other.com/", WebErrorStatus: WebErrorDisconnected}, false},
+		{"success", NavigationCompletedEvent{URL: "https://app.example.com/", Success: true}, false},
+	}
+	for _, tt := range tests {
+		if got := c.recoverable(&tt.e); got != tt.want {
+			t.Errorf("%s: recoverable = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	c.m.Lock()
+	c.entries["https://app.example.com/"].Stored = time.Now().Add(-2 * time.Hour)
+	c.m.Unlock()
+	if c.recoverable(&NavigationCompletedEvent{URL: "https://app.example.com/", WebErrorStatus: WebErrorDisconnected}) {
+		t.Error("recoverable with an expired cache")
+	}
+}
+
+func TestOfflineCacheOffline(t *testing.T) {
+	h := NewHeadless(WebViewOptions{Cache: &CacheOptions{
+		Dir:   t.TempDir(),
+		Rules: []CacheRule{{Pattern: "https://app.example.com/*"}},
+	}})
+	get := func(uri string) *http.Request { return httptest.NewRequest(http.MethodGet, uri, nil) }
+	online := func(uri, body string) {
+		res := &http.Response{StatusCode: 200, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}
+		if err := h.ReceiveResponse(get(uri), res); err != nil {
+			t.Fatal(err)
+		}
+	}
+	online("https://app.example.com/", "page")
+	online("https://app.example.com/data.json", "{}")
+	online("https://other.com/", "other")
+	if got := h.CacheStats().Entries; got != 2 {
+		t.Fatalf("Entries = %d, want 2", got)
+	}
+
+	// 在线时请求交给浏览器处理
+	if _, err := h.Fetch(get("https://app.example.com/data.json")); err == nil {
+		t.Error("cache was used while online")
+	}
+
+	// 网络错误时进入离线状态，重新打开有缓存的页面
+	h.NavigateError("https://app.example.com/", WebErrorDisconnected, 0)
+	if got := h.Navigations(); len(got) != 1 || got[0] != "https://app.example.com/" {
+		t.Errorf("Navigations() = %v, want the cached page reloaded", got)
+	}
+	if code, body := fetch(t, h, "https://app.example.com/data.json"); code != 200 || body != "{}" {
+		t.Errorf("offline fetch = %d %q", code, body)
+	}
+	if _, err := h.Fetch(get("https://app.example.com/missing")); err == nil {
+		t.Error("missing response served while offline")
+	}
+	if _, err := h.Fetch(get("https://other.com/")); err == nil {
+		t.Error("request without a rule served from the cache")
+	}
+	if got := h.CacheStats(); got.Hits != 1 || got.Misses != 1 {
+		t.Errorf("Stats() = %+v, want 1 hit and 1 miss", got)
+	}
+
+	// 离线状态持续 offlineDuration
+	h.cache.m.Lock()
+	until := time.Until(h.cache.offlineUntil)
+	h.cache.offlineUntil = time.Now().Add(-time.Second)
+	h.cache.m.Unlock()
+	if until <= offlineDuration-time.Minute/2 || until > offlineDuration {
+		t.Errorf("offline for %v, want %v", until, offlineDuration)
+	}
+	if _, err := h.Fetch(get("https://app.example.com/data.json")); err == nil {
+		t.Error("cache was used after the offline window")
+	}
+
+	// 再次收到网络响应时退出离线状态
+	h.NavigateError("https://app.example.com/", WebErrorDisconnected, 0)
+	online("https://app.example.com/data.json", "{}")
+	if _, err := h.Fetch(get("https://app.example.com/data.json")); err == nil {
+		t.Error("cache was used after a network response")
+	}
+}
+
+func TestResourceRouterUseFor(t *testing.T) {
+	r := &resourceRouter{}
+	r.useFor(func(next ResourceHandler) ResourceHandler { return next }, []string{"https://app.example.com/*"})
+	for uri, want := range map[string]bool{
+		"https://app.example.com/a": true,
+		"https://other.com/":        false,
+	} {
+		if got := r.intercepts(uri); got != want {
+			t.Errorf("intercepts(%q) = %v, want %v", uri, got, want)
+		}
+	}
+	if !r.use(func(next ResourceHandler) ResourceHandler { return next }) {
+		t.Error("use returned false for the first global middleware")
+	}
+	if !r.intercepts("https://other.com/") {
+		t.Error("global middleware does not intercept every request")
+	}
+}
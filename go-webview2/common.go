@@ -1,6 +1,9 @@
 package webview2
 
-import "unsafe"
+import (
+	"net/http"
+	"unsafe"
+)
 
 // This is copied from webview/webview.
 // The documentation is included for convenience.
@@ -65,7 +68,12 @@ type WebView interface {
 	// Init injects JavaScript code at the initialization of the new page. Every
 	// time the webview will open a the new page - this initialization code will
 	// be executed. It is guaranteed that code is executed before window.onload.
-	Init(js string)
+	// The returned id can be passed to RemoveInit.
+	Init(js string) ScriptID
+
+	// RemoveInit removes a script added by Init, it will not be executed on
+	// new pages. Pages already opened are not affected.
+	RemoveInit(id ScriptID)
 
 	// Eval evaluates arbitrary JavaScript code. Evaluation happens asynchronously,
 	// also the result of the expression is ignored. Use RPC bindings if you want
@@ -80,7 +88,60 @@ type WebView interface {
 	//
 	// f must be a function
 	// f must return either value and error or just error
-	Bind(name string, f interface{}) error
+	// if the first parameter of f is context.Context, it is canceled when the
+	// page navigates away, the window is destroyed or the JS caller aborts
+	Bind(name string, f interface{}, opts ...BindOption) error
+
+	// BindObject binds all exported methods of obj under window[ns], e.g.
+	// window.fs.readFile for method ReadFile of obj bound as "fs". Method
+	// names are lowerCamelCase by default, see BindCase and BindExclude.
+	BindObject(ns string, obj interface{}, opts ...BindOption) error
+
+	// Unbind removes a function bound by Bind, or all methods bound by
+	// BindObject when name is its namespace. The JavaScript function is
+	// deleted from the current page, calls already running are not affected.
+	Unbind(name string)
+
+	// Handle intercepts requests of the page whose URL matches pattern and
+	// serves them with handler. In pattern, * matches any characters and ?
+	// matches a single character, e.g. https://api.example.com/*
+	Handle(pattern string, handler http.Handler)
+
+	// Use adds a middleware for intercepted requests. Once a middleware is
+	// added every request of the page is intercepted, middlewares added
+	// first run first.
+	Use(mw ResourceMiddleware)
+
+	// OnNavigationStarting adds a listener called before the page navigates,
+	// the listener may cancel the navigation. It returns a function that
+	// removes the listener. Listeners run on the UI thread.
+	OnNavigationStarting(fn func(e *NavigationStartingEvent)) func()
+
+	// OnNavigationCompleted adds a listener called when a navigation
+	// succeeds or fails.
+	OnNavigationCompleted(fn func(e *NavigationCompletedEvent)) func()
+
+	// OnSourceChanged adds a listener called when the URL of the page changes.
+	OnSourceChanged(fn func(e *SourceChangedEvent)) func()
+
+	// OnDOMContentLoaded adds a listener called on DOMContentLoaded.
+	OnDOMContentLoaded(fn func(e *DOMContentLoadedEvent)) func()
+
+	// OnTitleChanged adds a listener called when document.title changes.
+	OnTitleChanged(fn func(title string)) func()
+
+	// CacheStats returns statistics of the offline cache enabled by
+	// WebViewOptions.Cache, or zero values when it is disabled.
+	CacheStats() CacheStats
+
+	// ClearCache removes all responses from the offline cache.
+	ClearCache() error
+
+	// AddVirtualHost maps https://host/ to the local folder vh.Folder.
+	AddVirtualHost(host string, vh VirtualHost) error
+
+	// RemoveVirtualHost removes a mapping added by AddVirtualHost.
+	RemoveVirtualHost(host string) error
 
 	Hide()
 	Show()
@@ -0,0 +1,176 @@
+package webview2
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// eventListener 是通过 On 或者 Once 注册的事件监听
+type eventListener struct {
+	pattern string
+	fn      func(payload json.RawMessage)
+	once    bool
+}
+
+// jsEvent 是页面通过 window.desktop.emit 发送给 Go 的事件
+type jsEvent struct {
+	name    string
+	payload json.RawMessage
+}
+
+// matchEvent 判断事件名是否匹配监听的事件，事件名以 . 分隔，
+// 监听的事件中 * 匹配任意一段，** 匹配任意多段（包括 0 段），如 user.* 匹配 user.login，** 匹配所有事件
+func matchEvent(pattern, event string) bool {
+	return matchEventSegments(strings.Split(pattern, "."), strings.Split(event, "."))
+}
+
+func matchEventSegments(p, e []string) bool {
+	for len(p) > 0 {
+		if p[0] == "**" {
+			if len(p) == 1 {
+				return true
+			}
+			for i := 0; i <= len(e); i++ {
+				if matchEventSegments(p[1:], e[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(e) == 0 || (p[0] != "*" && p[0] != e[0]) {
+			return false
+		}
+		p, e = p[1:], e[1:]
+	}
+	return len(e) == 0
+}
+
+// on 监听页面发送的事件，返回取消监听的函数
+func (b *bridge) on(pattern string, fn func(payload json.RawMessage), once bool) func() {
+	l := &eventListener{pattern: pattern, fn: fn, once: once}
+	b.m.Lock()
+	b.listeners = append(b.listeners, l)
+	b.m.Unlock()
+	return func() { b.off(l) }
+}
+
+func (b *bridge) off(l *eventListener) bool {
+	b.m.Lock()
+	defer b.m.Unlock()
+	for i, v := range b.listeners {
+		if v == l {
+			b.listeners = append(b.listeners[:i:i], b.listeners[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// emit 向页面发送事件，页面中通过 window.desktop.on 监听
+func (b *bridge) emit(event string, payload interface{}) error {
+	n, err := NewRPCNotification("$event", event, payload)
+	if err != nil {
+		return err
+	}
+	b.send(n)
+	return nil
+}
+
+// receiveEvent 处理页面发送的事件，事件按顺序交给 eventLoop 处理，不阻塞 UI 线程，
+// 监听函数处理不过来、等待处理的事件已满时丢弃新的事件
+func (b *bridge) receiveEvent(d *RPCMessage) {
+	params, err := d.ParamList()
+	if err != nil || len(params) == 0 {
+		return
+	}
+	var e jsEvent
+	if err := json.Unmarshal(params[0], &e.name); err != nil {
+		return
+	}
+	e.payload = json.RawMessage("null")
+	if len(params) > 1 {
+		e.payload = params[1]
+	}
+	select {
+	case b.events <- e:
+	default:
+		b.logger.Info("event " + e.name + " dropped, too many pending events")
+	}
+}
+
+func (b *bridge) eventLoop() {
+	for {
+		select {
+		case e := <-b.events:
+			b.m.Lock()
+			listeners := append([]*eventListener{}, b.listeners...)
+			b.m.Unlock()
+			for _, l := range listeners {
+				if !matchEvent(l.pattern, e.name) {
+					continue
+				}
+				if l.once && !b.off(l) {
+					// 已经被其他地方取消了监听
+					continue
+				}
+				l.fn(e.payload)
+			}
+		case <-b.ctx.Done():
+			return
+		}
+	}
+}
+
+// eventRuntime 是页面中的事件总线，依赖 rpcRuntime，
+// 提供 window.desktop.on、once、off、emit
+const eventRuntime = `(function() {
+	var desktop = window.desktop = window.desktop || {};
+	if (desktop.on) return;
+	var listeners = [];
+	var match = function(p, e) {
+		if (!p.length) return !e.length;
+		if (p[0] === "**") {
+			if (p.length === 1) return true;
+			for (var i = 0; i <= e.length; i++) {
+				if (match(p.slice(1), e.slice(i))) return true;
+			}
+			return false;
+		}
+		if (!e.length || (p[0] !== "*" && p[0] !== e[0])) return false;
+		return match(p.slice(1), e.slice(1));
+	};
+	var remove = function(l) {
+		var i = listeners.indexOf(l);
+		if (i < 0) return false;
+		listeners.splice(i, 1);
+		return true;
+	};
+	desktop.on = function(event, cb, once) {
+		var l = { event: event, cb: cb, once: !!once };
+		listeners.push(l);
+		return function() { remove(l); };
+	};
+	desktop.once = function(event, cb) {
+		return desktop.on(event, cb, true);
+	};
+	desktop.off = function(event, cb) {
+		listeners.slice().forEach(function(l) {
+			if (l.event === event && (!cb || l.cb === cb)) remove(l);
+		});
+	};
+	desktop.emit = function(event, payload) {
+		window._rpc.notify("$event", [event, payload === undefined ? null : payload]);
+	};
+	window._rpc.handlers["$event"] = function(event, payload) {
+		var e = event.split(".");
+		listeners.slice().forEach(function(l) {
+			if (!match(l.event.split("."), e)) return;
+			if (l.once && !remove(l)) return;
+			try {
+				l.cb(payload, event);
+			} catch (err) {
+				setTimeout(function() { throw err; });
+			}
+		});
+	};
+})()`
@@ -0,0 +1,119 @@
+package webview2
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMatchEvent(t *testing.T) {
+	tests := []struct {
+		pattern, event string
+		want           bool
+	}{
+		{"user.login", "user.login", true},
+		{"user.login", "user.logout", false},
+		{"user.*", "user.login", true},
+		{"user.*", "user", false},
+		{"user.*", "user.login.failed", false},
+		{"*.login", "admin.login", true},
+		{"user.**", "user", true},
+		{"user.**", "user.login.failed", true},
+		{"user.**", "admin.login", false},
+		{"**.failed", "user.login.failed", true},
+		{"**.failed", "failed", true},
+		{"a.**.z", "a.z", true},
+		{"a.**.z", "a.b.c.z", true},
+		{"a.**.z", "a.b.c", false},
+		{"**", "anything.at.all", true},
+		{"*", "one", true},
+		{"*", "one.two", false},
+	}
+	for _, tt := range tests {
+		if got := matchEvent(tt.pattern, tt.event); got != tt.want {
+			t.Errorf("matchEvent(%q, %q) = %v, want %v", tt.pattern, tt.event, got, tt.want)
+		}
+	}
+}
+
+// recorder 记录收到的事件，事件按顺序处理，收到 done 事件时之前的事件都已经处理完
+type recorder struct {
+	m    sync.Mutex
+	got  []string
+	done chan struct{}
+}
+
+func newRecorder(h *Headless) *recorder {
+	r := &recorder{done: make(chan struct{}, 1)}
+	h.On("done", func(json.RawMessage) { r.done <- struct{}{} })
+	return r
+}
+
+func (r *recorder) listen(name string) func(payload json.RawMessage) {
+	return func(payload json.RawMessage) {
+		r.m.Lock()
+		r.got = append(r.got, name+"="+string(payload))
+		r.m.Unlock()
+	}
+}
+
+func (r *recorder) wait(t *testing.T, h *Headless) string {
+	t.Helper()
+	h.EmitFromJS("done", nil)
+	select {
+	case <-r.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("events were not handled")
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	s := strings.Join(r.got, ",")
+	r.got = nil
+	return s
+}
+
+func TestHeadlessEvents(t *testing.T) {
+	h := NewHeadless(WebViewOptions{})
+	r := newRecorder(h)
+	h.On("user.*", r.listen("user"))
+	h.Once("user.login", r.listen("once"))
+	off := h.On("**", r.listen("all"))
+
+	h.EmitFromJS("user.login", 1)
+	h.EmitFromJS("user.login", 2)
+	if got, want := r.wait(t, h), "user=1,once=1,all=1,user=2,all=2,all=null"; got != want {
+		t.Errorf("events = %s, want %s", got, want)
+	}
+
+	off()
+	off()
+	h.EmitFromJS("user.logout", map[string]int{"id": 3})
+	h.EmitFromJS("other", nil)
+	if got, want := r.wait(t, h), `user={"id":3}`; got != want {
+		t.Errorf("events after off = %s, want %s", got, want)
+	}
+}
+
+// 监听函数处理不过来时丢弃事件，不阻塞发送事件的 UI 线程
+func TestHeadlessEventsFull(t *testing.T) {
+	h := NewHeadless(WebViewOptions{})
+	defer h.Destroy()
+	block := make(chan struct{})
+	defer close(block)
+	h.On("slow", func(json.RawMessage) { <-block })
+
+	sent := make(chan struct{})
+	go func() {
+		for i := 0; i < 1000; i++ {
+			h.EmitFromJS("slow", i)
+		}
+		close(sent)
+	}()
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("EmitFromJS blocked while the listener was busy")
+	}
+}
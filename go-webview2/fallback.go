@@ -0,0 +1,227 @@
+package webview2
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FallbackData 是页面跳转失败时传给错误页面模板的数据
+//
+//	<p>无法打开 {{.URL}}：{{.WebErrorStatus}}</p>
+//	<button onclick="window.desktop.retry()">重试</button>
+type FallbackData struct {
+	// URL 是跳转失败的地址
+	URL string
+	// WebErrorStatus 是失败的原因，在模板中输出为小写下划线格式的名称，如 host_name_not_resolved
+	WebErrorStatus WebErrorStatus
+	// HTTPStatus 是页面的 HTTP 状态码，没有收到响应时为 0
+	HTTPStatus int
+	// Attempt 是已经自动重试的次数
+	Attempt int
+	// RetryIn 是距离下次自动重试的时间，不再自动重试时为 0
+	RetryIn time.Duration
+}
+
+// FallbackOptions 是页面跳转失败时显示的错误页面和自动重试的配置，
+// 错误页面中可以调用 window.desktop.retry() 重新打开失败的地址
+type FallbackOptions struct {
+	// Page 是错误页面的 html/template 模板，数据为 FallbackData
+	Page string
+	// Pages 是不同失败原因使用的模板，没有对应的模板时使用 Page
+	Pages map[WebErrorStatus]string
+	// RetryMax 是自动重试的最大次数，为 0 时不自动重试
+	RetryMax int
+	// RetryDelay 是第一次自动重试的等待时间，之后每次翻倍，默认为 1 秒
+	RetryDelay time.Duration
+	// RetryMaxDelay 是自动重试的最长等待时间，默认为 30 秒
+	RetryMaxDelay time.Duration
+}
+
+// defaultFallbackPage 是只设置了自动重试，没有设置 Page 时使用的错误页面
+const defaultFallbackPage = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.URL}}</title></head>
+<body style="font-family: sans-serif; text-align: center; padding-top: 20vh; color: #333;">
+<p>{{.URL}}</p>
+<p>{{.WebErrorStatus}}{{if .HTTPStatus}} ({{.HTTPStatus}}){{end}}</p>
+{{if .RetryIn}}<p>{{.RetryIn}}</p>{{end}}
+<button onclick="window.desktop.retry()">Retry</button>
+</body>
+</html>`
+
+// fallbackRuntime 提供 window.desktop.retry，依赖 rpcRuntime
+const fallbackRuntime = `(function() {
+	var desktop = window.desktop = window.desktop || {};
+	desktop.retry = function() {
+		window._rpc.notify("$retry", []);
+	};
+})()`
+
+// fallbackOptions 返回 WebViewOptions 中错误页面的配置，没有配置时返回 nil，
+// Fallback 优先于 FallbackPage
+func fallbackOptions(options WebViewOptions) *FallbackOptions {
+	if options.Fallback != nil {
+		return options.Fallback
+	}
+	if options.FallbackPage != "" {
+		return &FallbackOptions{Page: options.FallbackPage}
+	}
+	return nil
+}
+
+// fallback 在页面跳转失败时显示错误页面，并按配置自动重试
+type fallback struct {
+	m       sync.Mutex
+	opts    FallbackOptions
+	page    *template.Template
+	pages   map[WebErrorStatus]*template.Template
+	url     string
+	attempt int
+	timer   *time.Timer
+
+	// show 显示错误页面，navigate 重新打开失败的地址，dispatch 把函数放到 UI 线程执行
+	show     func(html string)
+	navigate func(url string)
+	dispatch func(f func())
+	// recoverable 返回失败的跳转是否会由离线缓存重新打开，这时不显示错误页面
+	recoverable func(e *NavigationCompletedEvent) bool
+}
+
+// parseFallback 解析错误页面的模板，没有 {{ }} 的页面和普通的 html 一样
+func parseFallback(opts FallbackOptions) (*template.Template, map[WebErrorStatus]*template.Template, error) {
+	src := opts.Page
+	if src == "" {
+		src = defaultFallbackPage
+	}
+	page, err := template.New("fallback").Parse(src)
+	if err != nil {
+		return nil, nil, err
+	}
+	pages := map[WebErrorStatus]*template.Template{}
+	for status, src := range opts.Pages {
+		t, err := template.New("fallback_" + status.String()).Parse(src)
+		if err != nil {
+			return nil, nil, err
+		}
+		pages[status] = t
+	}
+	return page, pages, nil
+}
+
+// set 替换错误页面的配置，模板解析失败时不修改原来的配置
+func (f *fallback) set(opts FallbackOptions) error {
+	page, pages, err := parseFallback(opts)
+	if err != nil {
+		return err
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = time.Second
+	}
+	if opts.RetryMaxDelay <= 0 {
+		opts.RetryMaxDelay = 30 * time.Second
+	}
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.opts, f.page, f.pages = opts, page, pages
+	return nil
+}
+
+// listen 监听跳转完成的事件，跳转失败时显示错误页面
+func (f *fallback) listen(n *navigationEvents) {
+	n.OnNavigationCompleted(f.completed)
+}
+
+func (f *fallback) completed(e *NavigationCompletedEvent) {
+	if isInternalURL(e.URL) {
+		return
+	}
+	if e.Success {
+		f.m.Lock()
+		f.stop()
+		f.url, f.attempt = "", 0
+		f.m.Unlock()
+		return
+	}
+	if e.WebErrorStatus == WebErrorOperationCanceled {
+		// 被 OnNavigationStarting 或者 NavigationPolicy 取消的跳转不需要显示错误页面
+		return
+	}
+	if f.recoverable != nil && f.recoverable(e) {
+		return
+	}
+
+	f.m.Lock()
+	if f.url != e.URL {
+		f.url, f.attempt = e.URL, 0
+	}
+	f.stop()
+	data := FallbackData{URL: e.URL, WebErrorStatus: e.WebErrorStatus, HTTPStatus: e.HTTPStatus, Attempt: f.attempt}
+	if f.attempt < f.opts.RetryMax {
+		data.RetryIn = f.delay(f.attempt)
+		url := e.URL
+		f.timer = time.AfterFunc(data.RetryIn, func() {
+			f.dispatch(func() {
+				f.m.Lock()
+				if f.url != url {
+					f.m.Unlock()
+					return
+				}
+				f.attempt++
+				f.timer = nil
+				f.m.Unlock()
+				f.navigate(url)
+			})
+		})
+	}
+	t := f.page
+	if p, ok := f.pages[e.WebErrorStatus]; ok {
+		t = p
+	}
+	f.m.Unlock()
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		buf.Reset()
+		buf.WriteString(template.HTMLEscapeString(err.Error()))
+	}
+	f.show(buf.String())
+}
+
+// delay 返回第 attempt 次自动重试前的等待时间，需要持有锁
+func (f *fallback) delay(attempt int) time.Duration {
+	d := f.opts.RetryDelay
+	for i := 0; i < attempt && d < f.opts.RetryMaxDelay; i++ {
+		d *= 2
+	}
+	if d > f.opts.RetryMaxDelay {
+		d = f.opts.RetryMaxDelay
+	}
+	return d
+}
+
+// stop 取消还没执行的自动重试，需要持有锁
+func (f *fallback) stop() {
+	if f.timer != nil {
+		f.timer.Stop()
+		f.timer = nil
+	}
+}
+
+// retry 立即重新打开失败的地址，由 window.desktop.retry() 调用，没有失败的地址时忽略
+func (f *fallback) retry() {
+	f.m.Lock()
+	url := f.url
+	f.stop()
+	f.m.Unlock()
+	if url != "" {
+		f.dispatch(func() { f.navigate(url) })
+	}
+}
+
+// isInternalURL 判断是否是 SetHtml 等打开的内部页面，这些页面不需要显示错误页面
+func isInternalURL(url string) bool {
+	return url == "" || strings.HasPrefix(url, "about:") || strings.HasPrefix(url, "data:")
+}
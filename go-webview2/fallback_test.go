@@ -0,0 +1,150 @@
+package webview2
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFallbackDelay(t *testing.T) {
+	f := &fallback{}
+	if err := f.set(FallbackOptions{RetryDelay: time.Second, RetryMaxDelay: 5 * time.Second}); err != nil {
+		t.Fatal(err)
+	}
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := f.delay(attempt); got != want {
+			t.Errorf("delay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	// 默认第一次等待 1 秒，最长 30 秒
+	if err := f.set(FallbackOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.delay(0); got != time.Second {
+		t.Errorf("default delay(0) = %v", got)
+	}
+	if got := f.delay(10); got != 30*time.Second {
+		t.Errorf("default delay(10) = %v", got)
+	}
+
+	if err := f.set(FallbackOptions{Page: "{{.URL"}); err == nil {
+		t.Error("set accepted an invalid template")
+	}
+	if got := f.delay(0); got != time.Second {
+		t.Errorf("invalid template changed the options, delay(0) = %v", got)
+	}
+}
+
+func TestFallbackPages(t *testing.T) {
+	h := NewHeadless(WebViewOptions{Fallback: &FallbackOptions{
+		Page: "{{.URL}} {{.WebErrorStatus}} {{.HTTPStatus}}",
+		Pages: map[WebErrorStatus]string{
+			WebErrorTimeout: "timeout <b>{{.URL}}</b>",
+		},
+	}})
+	tests := []struct {
+		status     WebErrorStatus
+		httpStatus int
+		want       string
+	}{
+		{WebErrorHostNameNotResolved, 0, "https://a.example.com/ host_name_not_resolved 0"},
+		{WebErrorTimeout, 0, "timeout <b>https://a.example.com/</b>"},
+		{WebErrorUnknown, 404, "https://a.example.com/ unknown 404"},
+	}
+	for _, tt := range tests {
+		h.NavigateError("https://a.example.com/", tt.status, tt.httpStatus)
+		if got := h.HTML(); got != tt.want {
+			t.Errorf("%v page = %q, want %q", tt.status, got, tt.want)
+		}
+		if h.URL() != "about:blank" {
+			t.Errorf("URL() = %q, want about:blank", h.URL())
+		}
+	}
+
+	// 被取消的跳转不显示错误页面
+	h.Navigate("https://b.example.com/")
+	h.NavigateError("https://a.example.com/", WebErrorOperationCanceled, 0)
+	if h.URL() != "https://b.example.com/" {
+		t.Errorf("canceled navigation showed the fallback page")
+	}
+
+	// FallbackPage 是没有重试的错误页面
+	h = NewHeadless(WebViewOptions{FallbackPage: "<p>{{.URL}}</p>"})
+	h.NavigateError("https://a.example.com/<x>", WebErrorDisconnected, 0)
+	if got, want := h.HTML(), "<p>https://a.example.com/&lt;x&gt;</p>"; got != want {
+		t.Errorf("FallbackPage = %q, want %q", got, want)
+	}
+}
+
+func TestFallbackRetryMax(t *testing.T) {
+	var (
+		m     sync.Mutex
+		shown []string
+		tries int
+	)
+	done := make(chan struct{})
+	f := &fallback{dispatch: func(fn func()) { fn() }}
+	// 重新打开总是失败
+	f.navigate = func(url string) {
+		m.Lock()
+		tries++
+		m.Unlock()
+		f.completed(&NavigationCompletedEvent{URL: url, WebErrorStatus: WebErrorDisconnected})
+	}
+	if err := f.set(FallbackOptions{Page: "{{.Attempt}} {{.RetryIn}}", RetryMax: 3, RetryDelay: time.Millisecond, RetryMaxDelay: 2 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	f.show = func(html string) {
+		m.Lock()
+		shown = append(shown, html)
+		n := len(shown)
+		m.Unlock()
+		if n == 4 {
+			close(done)
+		}
+	}
+	f.completed(&NavigationCompletedEvent{URL: "https://a.example.com/", WebErrorStatus: WebErrorDisconnected})
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fallback did not retry")
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	m.Lock()
+	defer m.Unlock()
+	if tries != 3 {
+		t.Errorf("retried %d times, want 3", tries)
+	}
+	if got, want := strings.Join(shown, ","), "0 1ms,1 2ms,2 2ms,3 0s"; got != want {
+		t.Errorf("pages = %s, want %s", got, want)
+	}
+}
+
+func TestFallbackRetry(t *testing.T) {
+	h := NewHeadless(WebViewOptions{Fallback: &FallbackOptions{RetryMax: 1, RetryDelay: time.Hour}})
+	h.NavigateError("https://a.example.com/", WebErrorDisconnected, 0)
+	if !strings.Contains(h.HTML(), "window.desktop.retry()") {
+		t.Errorf("default page = %q", h.HTML())
+	}
+
+	// window.desktop.retry() 立即重新打开，并取消自动重试
+	h.PostMessage(`{"jsonrpc":"2.0","method":"$retry","params":[]}`)
+	if got := h.Navigations(); len(got) != 1 || got[0] != "https://a.example.com/" {
+		t.Fatalf("Navigations() = %v", got)
+	}
+	h.fallback.m.Lock()
+	timer, url := h.fallback.timer, h.fallback.url
+	h.fallback.m.Unlock()
+	if timer != nil || url != "" {
+		t.Errorf("fallback state after a successful retry: timer = %v, url = %q", timer, url)
+	}
+
+	// 成功打开后没有需要重试的地址
+	h.PostMessage(`{"jsonrpc":"2.0","method":"$retry","params":[]}`)
+	if got := h.Navigations(); len(got) != 1 {
+		t.Errorf("Navigations() = %v after retry without a failed url", got)
+	}
+}
@@ -0,0 +1,189 @@
+package webview2
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultResizeBorder 是无边框窗口没有设置 ResizeBorder 时边缘可以调整大小的宽度
+const defaultResizeBorder = 6
+
+// doubleClickTime 是连续两次按下标题栏视为双击的间隔，和 Windows 默认的双击间隔一致
+const doubleClickTime = 500 * time.Millisecond
+
+// 页面中的区域和调整大小的边缘，和 WM_NCHITTEST 的返回值对应
+const (
+	frameClient      = "client"
+	frameCaption     = "caption"
+	frameMaxButton   = "maxbutton"
+	frameTop         = "top"
+	frameBottom      = "bottom"
+	frameLeft        = "left"
+	frameRight       = "right"
+	frameTopLeft     = "topleft"
+	frameTopRight    = "topright"
+	frameBottomLeft  = "bottomleft"
+	frameBottomRight = "bottomright"
+	// frameToggle 是双击标题栏，最大化或者还原窗口
+	frameToggle = "maximize"
+)
+
+// frameRuntime 根据 css 的 -webkit-app-region 计算页面中的标题栏区域，通过 $regions 通知 Go，依赖 rpcRuntime。
+// drag 的元素作为标题栏，no-drag 的元素作为普通内容，data-app-region="maximize" 的元素作为最大化按钮，
+// 后面的元素覆盖前面的元素，所以 drag 区域中设置了 no-drag 的子元素仍然可以点击。
+// 窗口的 WM_NCHITTEST 根据这些区域返回 HTCAPTION 和 HTMAXBUTTON，页面中按下这些区域时通过 $frame
+// 通知 Go 按 WM_NCHITTEST 的结果处理，调整大小的边缘不在页面中，由窗口自己处理
+const frameRuntime = `(function() {
+	var desktop = window.desktop = window.desktop || {};
+	if (desktop.frame) return;
+	desktop.frame = {};
+	function region(el) {
+		if (el.getAttribute("data-app-region") === "maximize") return "maxbutton";
+		var style = getComputedStyle(el);
+		var value = style.getPropertyValue("-webkit-app-region") || style.getPropertyValue("app-region");
+		return value === "drag" ? "caption" : value === "no-drag" ? "client" : "";
+	}
+	function regions() {
+		var list = [], all = document.getElementsByTagName("*");
+		for (var i = 0; i < all.length; i++) {
+			var r = region(all[i]);
+			if (!r) continue;
+			var rect = all[i].getBoundingClientRect();
+			if (rect.width <= 0 || rect.height <= 0) continue;
+			list.push({ region: r, x: rect.left, y: rect.top, width: rect.width, height: rect.height });
+		}
+		return list;
+	}
+	function hit(el) {
+		for (; el && el.nodeType === 1; el = el.parentElement) {
+			var r = region(el);
+			if (r) return r;
+		}
+		return "client";
+	}
+	var last = "", pending = false;
+	function report() {
+		pending = false;
+		var list = regions(), json = JSON.stringify(list);
+		if (json === last) return;
+		last = json;
+		window._rpc.notify("$regions", [list]);
+	}
+	function schedule() {
+		if (pending) return;
+		pending = true;
+		requestAnimationFrame(report);
+	}
+	new MutationObserver(schedule).observe(document, { attributes: true, childList: true, subtree: true });
+	window.addEventListener("resize", schedule);
+	window.addEventListener("scroll", schedule, true);
+	window.addEventListener("load", schedule);
+	schedule();
+	window.addEventListener("mousedown", function(evt) {
+		if (evt.button !== 0 || hit(evt.target) === "client") return;
+		window._rpc.notify("$frame", [evt.clientX, evt.clientY]);
+		evt.preventDefault();
+		evt.stopPropagation();
+	}, true);
+})()`
+
+// frameRegion 是页面通过 $regions 通知的区域，坐标是相对页面左上角的 css 像素
+type frameRegion struct {
+	Region string  `json:"region"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// frame 是窗口的标题栏和边框设置
+type frame struct {
+	// frameless 表示窗口没有系统的标题栏和边框，border 是边缘可以调整大小的宽度，为 0 时不能通过边缘调整大小
+	frameless bool
+	border    int
+
+	m           sync.Mutex
+	regions     []frameRegion
+	lastCaption time.Time
+}
+
+func newFrame(opts WindowOptions) *frame {
+	f := &frame{frameless: opts.Frameless}
+	if f.frameless {
+		f.border = opts.ResizeBorder
+		if f.border == 0 {
+			f.border = defaultResizeBorder
+		}
+		if f.border < 0 {
+			f.border = 0
+		}
+	}
+	return f
+}
+
+// script 返回注入页面的 frameRuntime
+func (f *frame) script() string {
+	return frameRuntime
+}
+
+// setRegions 记录页面中的区域，页面每次变化时都会重新通知
+func (f *frame) setRegions(regions []frameRegion) {
+	f.m.Lock()
+	f.regions = regions
+	f.m.Unlock()
+}
+
+// regionAt 返回页面中 x、y 所在的区域，x、y 是 css 像素，有多个区域时后面的区域优先，
+// 不在标题栏或者最大化按钮中时返回 frameClient
+func (f *frame) regionAt(x, y float64) string {
+	f.m.Lock()
+	defer f.m.Unlock()
+	for i := len(f.regions) - 1; i >= 0; i-- {
+		r := f.regions[i]
+		if x < r.X || y < r.Y || x >= r.X+r.Width || y >= r.Y+r.Height {
+			continue
+		}
+		switch r.Region {
+		case frameCaption, frameMaxButton:
+			return r.Region
+		}
+		return frameClient
+	}
+	return frameClient
+}
+
+// caption 记录按下标题栏，间隔 doubleClickTime 以内两次按下时返回 true，表示双击
+func (f *frame) caption() bool {
+	f.m.Lock()
+	defer f.m.Unlock()
+	now := time.Now()
+	if now.Sub(f.lastCaption) < doubleClickTime {
+		f.lastCaption = time.Time{}
+		return true
+	}
+	f.lastCaption = now
+	return false
+}
+
+// hitTest 返回窗口中 x、y 所在的调整大小的边缘，x、y 相对窗口左上角，
+// width、height 是窗口大小，border 是边缘的宽度，都是物理像素，不在边缘时返回 frameClient
+func (f *frame) hitTest(x, y, width, height, border int) string {
+	if border <= 0 {
+		return frameClient
+	}
+	var v, h string
+	if y < border {
+		v = frameTop
+	} else if y >= height-border {
+		v = frameBottom
+	}
+	if x < border {
+		h = frameLeft
+	} else if x >= width-border {
+		h = frameRight
+	}
+	if v+h == "" {
+		return frameClient
+	}
+	return v + h
+}
@@ -0,0 +1,126 @@
+package webview2
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNewFrame(t *testing.T) {
+	tests := []struct {
+		name string
+		opts WindowOptions
+		want int
+	}{
+		{"framed", WindowOptions{ResizeBorder: 10}, 0},
+		{"default border", WindowOptions{Frameless: true}, defaultResizeBorder},
+		{"custom border", WindowOptions{Frameless: true, ResizeBorder: 10}, 10},
+		{"no border", WindowOptions{Frameless: true, ResizeBorder: -1}, 0},
+	}
+	for _, tt := range tests {
+		f := newFrame(tt.opts)
+		if f.frameless != tt.opts.Frameless || f.border != tt.want {
+			t.Errorf("%s: frameless = %v, border = %d, want %v, %d", tt.name, f.frameless, f.border, tt.opts.Frameless, tt.want)
+		}
+	}
+}
+
+func TestFrameRegionAt(t *testing.T) {
+	f := newFrame(WindowOptions{Frameless: true})
+	// 标题栏中有 no-drag 的菜单按钮和最大化按钮
+	f.setRegions([]frameRegion{
+		{Region: frameCaption, X: 0, Y: 0, Width: 800, Height: 32},
+		{Region: frameClient, X: 10, Y: 4, Width: 60, Height: 24},
+		{Region: frameMaxButton, X: 720, Y: 0, Width: 40, Height: 32},
+		{Region: "unknown", X: 400, Y: 0, Width: 10, Height: 10},
+	})
+	tests := []struct {
+		x, y float64
+		want string
+	}{
+		{100, 10, frameCaption},
+		{0, 0, frameCaption},
+		{799.5, 31.5, frameCaption},
+		{800, 10, frameClient},
+		{100, 32, frameClient},
+		{20, 10, frameClient},
+		{69, 27, frameClient},
+		{70, 10, frameCaption},
+		{730, 10, frameMaxButton},
+		{405, 5, frameClient},
+		{100, 300, frameClient},
+		{-1, 10, frameClient},
+	}
+	for _, tt := range tests {
+		if got := f.regionAt(tt.x, tt.y); got != tt.want {
+			t.Errorf("regionAt(%v, %v) = %s, want %s", tt.x, tt.y, got, tt.want)
+		}
+	}
+
+	f.setRegions(nil)
+	if got := f.regionAt(100, 10); got != frameClient {
+		t.Errorf("regionAt without regions = %s, want %s", got, frameClient)
+	}
+}
+
+func TestFrameHitTest(t *testing.T) {
+	f := newFrame(WindowOptions{Frameless: true})
+	const width, height, border = 800, 600, 6
+	tests := []struct {
+		x, y int
+		want string
+	}{
+		{400, 300, frameClient},
+		{6, 6, frameClient},
+		{793, 593, frameClient},
+		{0, 0, frameTopLeft},
+		{5, 5, frameTopLeft},
+		{799, 0, frameTopRight},
+		{794, 5, frameTopRight},
+		{0, 599, frameBottomLeft},
+		{799, 599, frameBottomRight},
+		{400, 0, frameTop},
+		{400, 5, frameTop},
+		{400, 594, frameBottom},
+		{0, 300, frameLeft},
+		{799, 300, frameRight},
+		{794, 300, frameRight},
+	}
+	for _, tt := range tests {
+		if got := f.hitTest(tt.x, tt.y, width, height, border); got != tt.want {
+			t.Errorf("hitTest(%d, %d) = %s, want %s", tt.x, tt.y, got, tt.want)
+		}
+	}
+	for _, b := range []int{0, -1} {
+		if got := f.hitTest(0, 0, width, height, b); got != frameClient {
+			t.Errorf("hitTest with border %d = %s, want %s", b, got, frameClient)
+		}
+	}
+}
+
+func TestHeadlessFrameHits(t *testing.T) {
+	h := NewHeadless(WebViewOptions{WindowOptions: WindowOptions{Frameless: true}})
+	h.PostMessage(`{"jsonrpc":"2.0","method":"$regions","params":[[` +
+		`{"region":"caption","x":0,"y":0,"width":800,"height":32},` +
+		`{"region":"maxbutton","x":760,"y":0,"width":40,"height":32}]]}`)
+	frameHit := func(x, y int) {
+		h.PostMessage(`{"jsonrpc":"2.0","method":"$frame","params":[` + strconv.Itoa(x) + `,` + strconv.Itoa(y) + `]}`)
+	}
+
+	frameHit(10, 100)
+	frameHit(10, 10)
+	if h.IsMaximized() {
+		t.Error("single click on the caption maximized the window")
+	}
+	frameHit(10, 10)
+	if !h.IsMaximized() {
+		t.Error("double click on the caption did not maximize the window")
+	}
+	frameHit(780, 10)
+	if h.IsMaximized() {
+		t.Error("max button did not restore the window")
+	}
+	if got, want := strings.Join(h.FrameHits(), ","), "caption,maximize,maxbutton"; got != want {
+		t.Errorf("FrameHits() = %s, want %s", got, want)
+	}
+}
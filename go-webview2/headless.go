@@ -0,0 +1,1155 @@
+package webview2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// memoryBrowser 是纯 Go 实现的 browser 后端，不依赖系统 webview，
+// 只把跳转、注入的脚本和执行的 js 记录在内存中
+type memoryBrowser struct {
+	m           sync.Mutex
+	url         string
+	html        string
+	navigations []string
+	scripts     []memoryScript
+	nextScript  int
+	evals       []string
+	callback    func(msg, source string)
+	// onEval 在每次执行 js 后调用，用于模拟页面对 Go 消息的处理
+	onEval func(script string)
+}
+
+var _ browser = &memoryBrowser{}
+
+func (b *memoryBrowser) Embed(hwnd uintptr) bool { return true }
+
+func (b *memoryBrowser) Resize() {}
+
+func (b *memoryBrowser) SetInset(inset int) {}
+
+func (b *memoryBrowser) Navigate(url string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.url = url
+	b.html = ""
+	b.navigations = append(b.navigations, url)
+	return nil
+}
+
+func (b *memoryBrowser) NavigateToString(htmlContent string) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.url = "about:blank"
+	b.html = htmlContent
+}
+
+type memoryScript struct {
+	id string
+	js string
+}
+
+func (b *memoryBrowser) Init(script string) {
+	b.AddScript(script, nil)
+}
+
+func (b *memoryBrowser) AddScript(script string, done func(id string)) {
+	b.m.Lock()
+	b.nextScript++
+	id := strconv.Itoa(b.nextScript)
+	b.scripts = append(b.scripts, memoryScript{id, script})
+	b.m.Unlock()
+	if done != nil {
+		done(id)
+	}
+}
+
+func (b *memoryBrowser) RemoveScript(id string) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	for i, s := range b.scripts {
+		if s.id == id {
+			b.scripts = append(b.scripts[:i:i], b.scripts[i+1:]...)
+			return
+		}
+	}
+}
+
+func (b *memoryBrowser) Eval(script string) {
+	b.m.Lock()
+	b.evals = append(b.evals, script)
+	onEval := b.onEval
+	b.m.Unlock()
+	if onEval != nil {
+		onEval(script)
+	}
+}
+
+func (b *memoryBrowser) NotifyParentWindowPositionChanged() error { return nil }
+
+func (b *memoryBrowser) Focus() {}
+
+type discardLogger struct{}
+
+func (discardLogger) Info(v ...interface{}) {}
+
+// JSHandler 模拟页面中可被 Go 调用的方法，params 为调用参数
+type JSHandler func(params []json.RawMessage) (interface{}, error)
+
+// Headless 是不依赖系统 webview 的窗口实现，所有操作只记录在内存中，
+// 可以在任意系统上运行，主要用于单元测试中驱动 Bind/Eval/Navigate 等流程。
+//
+// Headless 没有 UI 线程，Dispatch 的函数会在调用方的 goroutine 中立即执行。
+// 页面中的 js 不会真正执行，EvalAsync 和 CallJS 的结果需要通过 HandleEval 和 HandleCall 模拟
+type Headless struct {
+	browser *memoryBrowser
+	bridge  *bridge
+
+	m       sync.Mutex
+	title   string
+	width   int
+	height  int
+	visible bool
+	// disabled 表示窗口被模态窗口禁用
+	disabled bool
+	x, y     int
+	minSize  [2]int
+	maxSize  [2]int
+	state    headlessState
+	// fullscreen 表示窗口是否全屏，saved 是全屏前的位置和大小
+	fullscreen bool
+	saved      [4]int
+	onTop      bool
+	// persist 保存和恢复窗口状态，没有开启 PersistState 时为 nil
+	persist *windowState
+	// frame 是标题栏和边框的设置，frameHits 是页面中按下标题栏或者最大化按钮的记录
+	frame     *frame
+	frameHits []string
+
+	handlers map[string]JSHandler
+	// resources 处理通过 Handle 注册的地址的请求，包括 Assets 和 Handler 配置
+	resources *resourceRouter
+	// cache 是 WebViewOptions.Cache 开启的离线缓存
+	cache *OfflineCache
+	// vhosts 是通过 VirtualHosts 或 AddVirtualHost 映射的目录
+	vhosts map[string]VirtualHost
+	// replies 是通过 Call 发起的调用中还在等待响应的部分
+	replies map[string]chan *RPCMessage
+	// streams 是通过 Stream 发起的还在接收数据的调用
+	streams map[string]*headlessStream
+	nextID  uint64
+
+	done     chan struct{}
+	doneOnce sync.Once
+	// closed 在 Destroy 或者 Terminate 后调用，openWindow 在新的桌面窗口中打开页面，由 App 设置
+	closed     func()
+	openWindow func(opts WebViewOptions)
+	options    WebViewOptions
+	// result 是页面通过 window.desktop.close(result) 传入的值，用于 ShowModal
+	result *windowResult
+
+	navigationEvents
+	windowEvents
+	nextNavigation uint64
+	policy         *NavigationPolicy
+	fallback       *fallback
+	// externals 是在系统默认浏览器中打开的地址，popups 是在新的桌面窗口中打开的地址
+	externals []string
+	popups    []string
+}
+
+// NewHeadless 创建一个无界面的 webview，参数和 NewWithOptions 一致
+func NewHeadless(options WebViewOptions) *Headless {
+	l := options.Logger
+	if l == nil {
+		l = discardLogger{}
+	}
+	h := &Headless{
+		browser:  &memoryBrowser{},
+		title:    options.WindowOptions.Title,
+		width:    int(options.WindowOptions.Width),
+		height:   int(options.WindowOptions.Height),
+		visible:  !options.WindowOptions.StartHidden,
+		onTop:    options.WindowOptions.AlwaysOnTop,
+		handlers: map[string]JSHandler{},
+		replies:  map[string]chan *RPCMessage{},
+		streams:  map[string]*headlessStream{},
+		done:     make(chan struct{}),
+		options:  options,
+		result:   newWindowResult(),
+		frame:    newFrame(options.WindowOptions),
+	}
+	if options.WindowOptions.Center {
+		h.Center()
+	}
+	if h.persist = newWindowState(options); h.persist != nil {
+		screen := Bounds{Width: headlessScreen[0], Height: headlessScreen[1]}
+		if s, ok := h.persist.restore([]Bounds{screen}, l); ok {
+			h.x, h.y, h.width, h.height = s.X, s.Y, s.Width, s.Height
+			if s.Maximized {
+				h.state = headlessMaximized
+			}
+			if s.Fullscreen {
+				h.SetFullscreen(true)
+			}
+		}
+	}
+	h.resources = &resourceRouter{}
+	h.vhosts = map[string]VirtualHost{}
+	app := appHandler(options)
+	if app != nil {
+		h.resources.handle(AppOrigin+"/*", app)
+	}
+	h.bridge = newBridge(h.browser, h.Dispatch, l)
+	h.bridge.origins = options.AllowedOrigins
+	h.bridge.closeWindow = func(result json.RawMessage) {
+		h.result.set(result)
+		if !h.closeRequested() {
+			h.result.set(nil)
+			return
+		}
+		h.Destroy()
+	}
+	h.windowEvents.send = h.bridge.windowEvent
+	h.bridge.frame = h.frameHit
+	h.bridge.regions = h.frame.setRegions
+	h.browser.callback = h.bridge.msgcb
+	h.browser.onEval = h.handleScript
+	h.bridge.setup()
+	h.browser.Init(h.frame.script())
+	if options.Modal && options.Parent != nil {
+		options.Parent.setEnabled(false)
+	}
+	for host, vh := range options.VirtualHosts {
+		if err := h.AddVirtualHost(host, vh); err != nil {
+			l.Info("add virtual host "+host+" failed:", err)
+		}
+	}
+	if fb := fallbackOptions(options); fb != nil {
+		h.fallback = &fallback{show: h.SetHtml, navigate: h.Navigate, dispatch: h.Dispatch, recoverable: h.cacheRecoverable}
+		if err := h.fallback.set(*fb); err != nil {
+			l.Info("invalid fallback page:", err)
+		}
+		h.bridge.retry = h.fallback.retry
+		h.fallback.listen(&h.navigationEvents)
+	}
+	if options.NavigationPolicy != nil {
+		h.policy = options.NavigationPolicy
+		h.policy.enforce(&h.navigationEvents, h.openExternal)
+	}
+	var err error
+	if h.cache, err = newCache(options); err != nil {
+		l.Info("open offline cache failed:", err)
+	} else if h.cache != nil {
+		h.resources.useFor(h.cache.Middleware, h.cache.patterns())
+		h.cache.listen(&h.navigationEvents, h.Navigate)
+	}
+
+	if options.StartURL != "" {
+		h.Navigate(options.StartURL)
+	} else if options.StartHTML != "" {
+		h.SetHtml(options.StartHTML)
+	} else if app != nil {
+		h.Navigate(AppOrigin + "/")
+	}
+	return h
+}
+
+// Handle 拦截页面中地址匹配 pattern 的请求，交给 handler 处理，
+// pattern 中 * 匹配任意多个字符，? 匹配一个字符，如 https://api.example.com/*
+func (h *Headless) Handle(pattern string, handler http.Handler) {
+	h.resources.handle(pattern, handler)
+}
+
+// Use 注册拦截请求的中间件，Fetch 和 FetchResource 的请求都会经过中间件
+func (h *Headless) Use(mw ResourceMiddleware) {
+	h.resources.use(mw)
+}
+
+// CacheStats 返回离线缓存的统计信息，没有开启离线缓存时返回零值
+func (h *Headless) CacheStats() CacheStats {
+	if h.cache == nil {
+		return CacheStats{}
+	}
+	return h.cache.Stats()
+}
+
+// ClearCache 删除离线缓存中的所有响应
+func (h *Headless) ClearCache() error {
+	if h.cache == nil {
+		return nil
+	}
+	return h.cache.Clear()
+}
+
+// AddVirtualHost 把 https://host/ 映射到本地目录，Fetch 时从目录中读取文件，host 为空或者目录不存在时返回错误
+func (h *Headless) AddVirtualHost(host string, vh VirtualHost) error {
+	folder, err := virtualHostFolder(host, vh)
+	if err != nil {
+		return err
+	}
+	h.m.Lock()
+	h.vhosts[host] = VirtualHost{Folder: folder, Access: vh.Access}
+	h.m.Unlock()
+	h.resources.handle("https://"+host+"/*", virtualHostHandler(folder))
+	return nil
+}
+
+// RemoveVirtualHost 移除 AddVirtualHost 映射的目录，host 为空时返回错误
+func (h *Headless) RemoveVirtualHost(host string) error {
+	if host == "" {
+		return errors.New("virtual host name is empty")
+	}
+	h.m.Lock()
+	delete(h.vhosts, host)
+	h.m.Unlock()
+	h.resources.remove("https://" + host + "/*")
+	return nil
+}
+
+// VirtualHosts 返回当前映射的虚拟主机，目录为绝对路径
+func (h *Headless) VirtualHosts() map[string]VirtualHost {
+	h.m.Lock()
+	defer h.m.Unlock()
+	m := make(map[string]VirtualHost, len(h.vhosts))
+	for host, vh := range h.vhosts {
+		m[host] = vh
+	}
+	return m
+}
+
+// Fetch 模拟页面用 fetch 发出请求，等同于 FetchResource(req, ResourceFetch)
+func (h *Headless) Fetch(req *http.Request) (*http.Response, error) {
+	return h.FetchResource(req, ResourceFetch)
+}
+
+// FetchResource 模拟页面发出类型为 ctx 的请求，请求依次经过 Use 注册的中间件和
+// Handle、Assets 或 Handler 注册的处理，和 webview 中一样经过相同的请求和响应转换。
+// Headless 不会访问网络，请求被放行时返回错误
+func (h *Headless) FetchResource(req *http.Request, ctx ResourceContext) (*http.Response, error) {
+	rr, err := newRawRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	r, err := rr.httpRequest(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	res := h.resources.serve(&ResourceRequest{Request: r, Context: ctx})
+	if res == nil {
+		return nil, errors.New("no handler for " + req.URL.String())
+	}
+	return res.httpResponse(req), nil
+}
+
+// ReceiveResponse 模拟浏览器从网络收到 req 的响应，开启了离线缓存时和 webview 中一样保存匹配规则的响应，
+// 并退出离线状态
+func (h *Headless) ReceiveResponse(req *http.Request, res *http.Response) error {
+	if h.cache == nil {
+		return nil
+	}
+	if !h.cache.received(req.Method, req.URL.String(), res.StatusCode, res.Header) {
+		return nil
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	h.cache.store(req.URL.String(), res.StatusCode, res.Header, body)
+	return nil
+}
+
+// cacheRecoverable 返回失败的跳转是否会由离线缓存重新打开
+func (h *Headless) cacheRecoverable(e *NavigationCompletedEvent) bool {
+	return h.cache != nil && h.cache.recoverable(e)
+}
+
+// Run 阻塞直到调用了 Destroy 或者 Terminate
+func (h *Headless) Run() {
+	<-h.done
+}
+
+func (h *Headless) Terminate() {
+	h.doneOnce.Do(func() {
+		h.saveState()
+		h.bridge.close()
+		if h.options.Modal && h.options.Parent != nil {
+			h.options.Parent.setEnabled(true)
+		}
+		h.result.closed()
+		close(h.done)
+		if h.closed != nil {
+			h.closed()
+		}
+	})
+}
+
+func (h *Headless) Destroy() {
+	h.Terminate()
+}
+
+// RequestClose 模拟用户点击关闭按钮，OnCloseRequested 的监听返回 false 时不关闭并返回 false，
+// 否则和 Windows 一样在 HideWindowOnClose 时隐藏窗口，不然销毁窗口
+func (h *Headless) RequestClose() bool {
+	if !h.closeRequested() {
+		return false
+	}
+	if h.options.HideWindowOnClose && !h.options.Modal {
+		h.Hide()
+	} else {
+		h.Destroy()
+	}
+	return true
+}
+
+// frameHit 根据页面通知的区域记录按下的位置，双击标题栏或者按下最大化按钮时和 Windows 一样最大化或者还原窗口
+func (h *Headless) frameHit(x, y float64) {
+	hit := h.frame.regionAt(x, y)
+	switch {
+	case hit == frameClient:
+		return
+	case hit == frameCaption && h.frame.caption():
+		hit = frameToggle
+	}
+	h.m.Lock()
+	h.frameHits = append(h.frameHits, hit)
+	h.m.Unlock()
+	if hit == frameCaption {
+		return
+	}
+	if h.IsMaximized() {
+		h.Restore()
+	} else {
+		h.Maximize()
+	}
+}
+
+// FrameHits 返回页面中按下标题栏或者最大化按钮的记录，按下标题栏时为 caption，双击标题栏时为 maximize，
+// 按下最大化按钮时为 maxbutton，可以通过 PostMessage 发送 $regions 和 $frame 通知模拟
+func (h *Headless) FrameHits() []string {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return append([]string{}, h.frameHits...)
+}
+
+// Focus 模拟窗口获得焦点，触发 OnFocus
+func (h *Headless) Focus() {
+	h.focused()
+}
+
+// Blur 模拟窗口失去焦点，触发 OnBlur
+func (h *Headless) Blur() {
+	h.blurred()
+}
+
+// bounds 返回窗口的位置和大小
+func (h *Headless) bounds() [4]int {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return [4]int{h.x, h.y, h.width, h.height}
+}
+
+// changed 比较 before 和当前的位置、大小，触发 OnMove 和 OnResize
+func (h *Headless) changed(before [4]int) {
+	after := h.bounds()
+	if after[0] != before[0] || after[1] != before[1] {
+		h.moved(after[0], after[1])
+	}
+	if after[2] != before[2] || after[3] != before[3] {
+		h.resized(after[2], after[3])
+	}
+}
+
+// setState 修改最小化、最大化状态，离开或者进入最小化时触发 OnRestore 和 OnMinimize
+func (h *Headless) setState(state headlessState) {
+	h.m.Lock()
+	prev := h.state
+	h.state = state
+	h.m.Unlock()
+	switch {
+	case state == headlessMinimized && prev != headlessMinimized:
+		h.minimized()
+	case state != headlessMinimized && prev == headlessMinimized:
+		h.restored()
+	}
+}
+
+// ShowModal 显示窗口并等待窗口关闭，返回页面通过 window.desktop.close(result) 传入的值，
+// 可以通过 PostMessage 发送 $close 通知模拟页面关闭窗口，直接调用 Destroy 时返回 nil
+func (h *Headless) ShowModal() json.RawMessage {
+	h.Show()
+	return h.result.wait()
+}
+
+func (h *Headless) nativeHandle() uintptr {
+	return 0
+}
+
+func (h *Headless) setEnabled(enabled bool) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.disabled = !enabled
+}
+
+// Enabled 返回窗口是否可以响应用户输入，模态窗口打开期间 Parent 为 false
+func (h *Headless) Enabled() bool {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return !h.disabled
+}
+
+func (h *Headless) Dispatch(f func()) {
+	f()
+}
+
+func (h *Headless) SetTitle(title string) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.title = title
+}
+
+// SetSize 设置窗口大小，HintMin 和 HintMax 设置最小值和最大值，之后设置的大小会被限制在范围内
+func (h *Headless) SetSize(width int, height int, hint Hint) {
+	defer h.changed(h.bounds())
+	h.m.Lock()
+	defer h.m.Unlock()
+	switch hint {
+	case HintMin:
+		h.minSize = [2]int{width, height}
+		return
+	case HintMax:
+		h.maxSize = [2]int{width, height}
+		return
+	}
+	if h.minSize[0] > 0 && h.minSize[1] > 0 {
+		width, height = maxInt(width, h.minSize[0]), maxInt(height, h.minSize[1])
+	}
+	if h.maxSize[0] > 0 && h.maxSize[1] > 0 {
+		width, height = minInt(width, h.maxSize[0]), minInt(height, h.maxSize[1])
+	}
+	h.width = width
+	h.height = height
+}
+
+// headlessState 是 Headless 窗口的最小化、最大化状态
+type headlessState int
+
+const (
+	headlessNormal headlessState = iota
+	headlessMinimized
+	headlessMaximized
+)
+
+// headlessScreen 是 Headless 模拟的屏幕大小，用于 Center 和 SetFullscreen
+var headlessScreen = [2]int{1920, 1080}
+
+func (h *Headless) SetMinSize(width int, height int) {
+	h.SetSize(width, height, HintMin)
+}
+
+func (h *Headless) SetMaxSize(width int, height int) {
+	h.SetSize(width, height, HintMax)
+}
+
+func (h *Headless) SetPosition(x int, y int) {
+	defer h.changed(h.bounds())
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.x, h.y = x, y
+}
+
+func (h *Headless) GetPosition() (x int, y int) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.x, h.y
+}
+
+func (h *Headless) GetSize() (width int, height int) {
+	return h.Size()
+}
+
+func (h *Headless) Minimize() {
+	h.setState(headlessMinimized)
+}
+
+func (h *Headless) Maximize() {
+	h.setState(headlessMaximized)
+}
+
+func (h *Headless) Restore() {
+	h.setState(headlessNormal)
+}
+
+func (h *Headless) IsMaximized() bool {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.state == headlessMaximized
+}
+
+// IsMinimized 返回窗口是否最小化
+func (h *Headless) IsMinimized() bool {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.state == headlessMinimized
+}
+
+// SetFullscreen 全屏时窗口的位置和大小为 headlessScreen，退出全屏时恢复
+func (h *Headless) SetFullscreen(fullscreen bool) {
+	defer h.changed(h.bounds())
+	h.m.Lock()
+	defer h.m.Unlock()
+	if fullscreen == h.fullscreen {
+		return
+	}
+	if fullscreen {
+		h.saved = [4]int{h.x, h.y, h.width, h.height}
+		h.x, h.y, h.width, h.height = 0, 0, headlessScreen[0], headlessScreen[1]
+	} else {
+		h.x, h.y, h.width, h.height = h.saved[0], h.saved[1], h.saved[2], h.saved[3]
+	}
+	h.fullscreen = fullscreen
+}
+
+// saveState 在窗口关闭时保存窗口状态
+func (h *Headless) saveState() {
+	if h.persist == nil {
+		return
+	}
+	h.m.Lock()
+	s := WindowState{
+		Bounds:     Bounds{X: h.x, Y: h.y, Width: h.width, Height: h.height},
+		Maximized:  h.state == headlessMaximized,
+		Fullscreen: h.fullscreen,
+		Monitor:    Bounds{Width: headlessScreen[0], Height: headlessScreen[1]},
+	}
+	if h.fullscreen {
+		s.Bounds = Bounds{X: h.saved[0], Y: h.saved[1], Width: h.saved[2], Height: h.saved[3]}
+	}
+	h.m.Unlock()
+	h.persist.save(s, h.bridge.logger)
+}
+
+// IsFullscreen 返回窗口是否全屏
+func (h *Headless) IsFullscreen() bool {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.fullscreen
+}
+
+// Center 把窗口移动到 headlessScreen 的中间
+func (h *Headless) Center() {
+	defer h.changed(h.bounds())
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.x = (headlessScreen[0] - h.width) / 2
+	h.y = (headlessScreen[1] - h.height) / 2
+}
+
+func (h *Headless) SetAlwaysOnTop(onTop bool) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.onTop = onTop
+}
+
+// IsAlwaysOnTop 返回窗口是否总是显示在其他窗口上面
+func (h *Headless) IsAlwaysOnTop() bool {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.onTop
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Navigate 模拟跳转到 url，依次触发 OnNavigationStarting、OnSourceChanged、OnDOMContentLoaded
+// 和 OnNavigationCompleted 的监听，上一个页面还没执行完的 Go 函数会被取消，跳转被取消时不会修改当前地址
+func (h *Headless) Navigate(url string) {
+	h.navigate(url, func() { _ = h.browser.Navigate(url) })
+}
+
+func (h *Headless) SetHtml(html string) {
+	h.navigate("about:blank", func() { h.browser.NavigateToString(html) })
+}
+
+// NavigateError 模拟跳转到 url 失败，status 为失败原因，httpStatus 为页面的 HTTP 状态码，
+// 会触发 OnNavigationStarting 和 OnNavigationCompleted 的监听，配置了错误页面时显示错误页面
+func (h *Headless) NavigateError(url string, status WebErrorStatus, httpStatus int) {
+	id, ok := h.startNavigation(url)
+	if !ok {
+		return
+	}
+	h.navigationCompleted(&NavigationCompletedEvent{NavigationID: id, WebErrorStatus: status, HTTPStatus: httpStatus})
+}
+
+// startNavigation 触发 OnNavigationStarting 的监听，跳转被取消时返回 false
+func (h *Headless) startNavigation(url string) (uint64, bool) {
+	h.m.Lock()
+	h.nextNavigation++
+	id := h.nextNavigation
+	h.m.Unlock()
+	if h.navigationStarting(&NavigationStartingEvent{URL: url, NavigationID: id}) {
+		h.navigationCompleted(&NavigationCompletedEvent{NavigationID: id, WebErrorStatus: WebErrorOperationCanceled})
+		return id, false
+	}
+	return id, true
+}
+
+// navigate 模拟 WebView2 跳转时的事件顺序
+func (h *Headless) navigate(url string, load func()) {
+	id, ok := h.startNavigation(url)
+	if !ok {
+		return
+	}
+	load()
+	h.bridge.reset()
+	h.bridge.setPage(url)
+	h.sourceChanged(&SourceChangedEvent{URL: url, IsNewDocument: true})
+	h.domContentLoaded(&DOMContentLoadedEvent{NavigationID: id})
+	h.navigationCompleted(&NavigationCompletedEvent{NavigationID: id, Success: true, HTTPStatus: 200})
+}
+
+// OpenWindow 模拟页面通过 window.open 或者 target=_blank 打开 url，按 NavigationPolicy.NewWindow 处理，
+// 没有设置 NavigationPolicy 时和 WebView2 一样在新窗口中打开，通过 App 创建的窗口会在 App 中创建新窗口
+func (h *Headless) OpenWindow(url string) {
+	action := NewWindowDesktop
+	if h.policy != nil {
+		action = h.policy.newWindowAction(url)
+	}
+	switch action {
+	case NewWindowCurrent:
+		h.Navigate(url)
+	case NewWindowDesktop:
+		h.m.Lock()
+		h.popups = append(h.popups, url)
+		h.m.Unlock()
+		if h.openWindow != nil {
+			h.openWindow(popupOptions(h.options, url))
+		}
+	default:
+		h.openExternal(url)
+	}
+}
+
+// openExternal 记录在系统默认浏览器中打开的地址，NavigationPolicy.ExternalSchemes 以外的协议只记录日志
+func (h *Headless) openExternal(url string) {
+	if !h.policy.external(url) {
+		h.bridge.logger.Info("navigation to " + url + " is blocked by navigation policy")
+		return
+	}
+	h.m.Lock()
+	h.externals = append(h.externals, url)
+	h.m.Unlock()
+}
+
+// ExternalURLs 返回被 NavigationPolicy 取消并在系统默认浏览器中打开的地址
+func (h *Headless) ExternalURLs() []string {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return append([]string{}, h.externals...)
+}
+
+// Popups 返回通过 OpenWindow 在新窗口中打开的地址
+func (h *Headless) Popups() []string {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return append([]string{}, h.popups...)
+}
+
+// SetDocumentTitle 模拟页面修改 document.title，触发 OnTitleChanged 的监听
+func (h *Headless) SetDocumentTitle(title string) {
+	h.titleChanged(title)
+}
+
+func (h *Headless) Init(js string) ScriptID {
+	return h.bridge.init(js)
+}
+
+// RemoveInit 移除通过 Init 注入的脚本
+func (h *Headless) RemoveInit(id ScriptID) {
+	h.bridge.removeScript(id)
+}
+
+func (h *Headless) Eval(js string) {
+	h.browser.Eval(js)
+}
+
+// Bind 绑定 Go 函数 f，f 不是可以绑定的函数时返回错误
+func (h *Headless) Bind(name string, f interface{}, opts ...BindOption) error {
+	return h.bridge.bind(name, f, opts...)
+}
+
+// BindObject 把 obj 导出的方法绑定到页面的 window[ns] 对象下
+func (h *Headless) BindObject(ns string, obj interface{}, opts ...BindOption) error {
+	return h.bridge.bindObject(ns, obj, opts...)
+}
+
+// Unbind 移除绑定的函数，name 为 BindObject 的 ns 时移除该对象下所有的函数
+func (h *Headless) Unbind(name string) {
+	h.bridge.unbind(name)
+}
+
+// Bindings 返回已绑定的 Go 函数，key 为页面中的函数名
+func (h *Headless) Bindings() map[string]interface{} {
+	return h.bridge.funcs()
+}
+
+func (h *Headless) Hide() {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.visible = false
+}
+
+func (h *Headless) Show() {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.visible = true
+}
+
+// PostMessage 模拟页面调用 window.external.invoke(msg) 向 Go 发送消息，
+// msg 需要符合 RPCMessage 的格式。
+// 绑定的 Go 函数在新的 goroutine 中执行，执行结果需要通过 Messages 或者 Call 获取
+func (h *Headless) PostMessage(msg string) {
+	h.PostMessageFrom(h.URL(), msg)
+}
+
+// PostMessageFrom 和 PostMessage 一样，但是模拟地址为 source 的页面发送消息，
+// 用于测试 BindOrigins 等来源限制
+func (h *Headless) PostMessageFrom(source, msg string) {
+	h.browser.m.Lock()
+	cb := h.browser.callback
+	h.browser.m.Unlock()
+	if cb != nil {
+		cb(msg, source)
+	}
+}
+
+// Title 返回当前窗口标题
+func (h *Headless) Title() string {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.title
+}
+
+// Size 返回当前窗口大小
+func (h *Headless) Size() (width int, height int) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.width, h.height
+}
+
+// Visible 返回窗口是否处于显示状态
+func (h *Headless) Visible() bool {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.visible
+}
+
+// URL 返回当前页面地址，通过 SetHtml 设置的页面为 about:blank
+func (h *Headless) URL() string {
+	h.browser.m.Lock()
+	defer h.browser.m.Unlock()
+	return h.browser.url
+}
+
+// HTML 返回最后一次通过 SetHtml 设置的页面内容
+func (h *Headless) HTML() string {
+	h.browser.m.Lock()
+	defer h.browser.m.Unlock()
+	return h.browser.html
+}
+
+// Navigations 返回所有通过 Navigate 跳转过的 url
+func (h *Headless) Navigations() []string {
+	h.browser.m.Lock()
+	defer h.browser.m.Unlock()
+	return append([]string{}, h.browser.navigations...)
+}
+
+// Scripts 返回所有通过 Init 注入且没有被移除的脚本，包括 Bind 生成的脚本
+func (h *Headless) Scripts() []string {
+	h.browser.m.Lock()
+	defer h.browser.m.Unlock()
+	scripts := make([]string, len(h.browser.scripts))
+	for i, s := range h.browser.scripts {
+		scripts[i] = s.js
+	}
+	return scripts
+}
+
+// Evals 返回所有在页面执行过的 js，包括 Go 函数返回值的回调脚本
+func (h *Headless) Evals() []string {
+	h.browser.m.Lock()
+	defer h.browser.m.Unlock()
+	return append([]string{}, h.browser.evals...)
+}
+
+// Messages 返回 Go 发送给页面 RPC 运行时的所有消息，如绑定函数调用的响应
+func (h *Headless) Messages() []*RPCMessage {
+	msgs := []*RPCMessage{}
+	for _, js := range h.Evals() {
+		if m, ok := parseRPCScript(js); ok {
+			msgs = append(msgs, m)
+		}
+	}
+	return msgs
+}
+
+// Emit 向页面发送事件，可以通过 Messages 查看发送的事件
+func (h *Headless) Emit(event string, payload interface{}) error {
+	return h.bridge.emit(event, payload)
+}
+
+func (h *Headless) On(event string, fn func(payload json.RawMessage)) func() {
+	return h.bridge.on(event, fn, false)
+}
+
+func (h *Headless) Once(event string, fn func(payload json.RawMessage)) func() {
+	return h.bridge.on(event, fn, true)
+}
+
+// EmitFromJS 模拟页面调用 window.desktop.emit(event, payload) 发送事件，
+// 监听函数在单独的 goroutine 中按顺序执行
+func (h *Headless) EmitFromJS(event string, payload interface{}) {
+	h.postNotification("$event", event, payload)
+}
+
+// EvalAsync 模拟在页面执行 js 并返回结果，结果由 HandleEval 设置的函数提供
+func (h *Headless) EvalAsync(js string) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultEvalTimeout)
+	defer cancel()
+	return h.EvalWithContext(ctx, js)
+}
+
+func (h *Headless) EvalWithContext(ctx context.Context, js string) (json.RawMessage, error) {
+	return h.bridge.evalAsync(ctx, js)
+}
+
+// CallJS 模拟调用页面函数，结果由 HandleCall 设置的函数提供
+func (h *Headless) CallJS(ctx context.Context, fn string, args ...interface{}) (json.RawMessage, error) {
+	return h.bridge.callJS(ctx, fn, args...)
+}
+
+// HandleEval 设置 EvalAsync 执行 js 时的模拟结果
+func (h *Headless) HandleEval(fn func(js string) (interface{}, error)) {
+	h.handle("$eval", func(params []json.RawMessage) (interface{}, error) {
+		var js string
+		if len(params) > 0 {
+			_ = json.Unmarshal(params[0], &js)
+		}
+		return fn(js)
+	})
+}
+
+// HandleCall 设置页面函数 name 被 CallJS 调用时的模拟实现
+func (h *Headless) HandleCall(name string, fn JSHandler) {
+	h.handle("$call."+name, fn)
+}
+
+func (h *Headless) handle(method string, fn JSHandler) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.handlers[method] = fn
+}
+
+// handleScript 模拟页面 RPC 运行时处理 Go 发来的请求，并把结果回复给 Go
+func (h *Headless) handleScript(js string) {
+	req, ok := parseRPCScript(js)
+	if !ok {
+		return
+	}
+	if req.IsResponse() {
+		h.m.Lock()
+		ch, ok := h.replies[string(req.ID)]
+		delete(h.replies, string(req.ID))
+		h.m.Unlock()
+		if ok {
+			ch <- req
+		}
+		return
+	}
+	if req.Method == "$stream" {
+		h.handleStream(req)
+		return
+	}
+	if !req.IsRequest() {
+		return
+	}
+	params, _ := req.ParamList()
+	method := req.Method
+	if method == "$call" && len(params) > 0 {
+		var name string
+		_ = json.Unmarshal(params[0], &name)
+		method = "$call." + name
+		params = params[1:]
+		if len(params) > 0 {
+			var args []json.RawMessage
+			_ = json.Unmarshal(params[0], &args)
+			params = args
+		}
+	}
+	h.m.Lock()
+	fn, ok := h.handlers[method]
+	h.m.Unlock()
+
+	var res *RPCMessage
+	if !ok {
+		res = NewRPCResponse(req.ID, nil, &RPCError{Code: RPCMethodNotFound, Message: "method not found: " + method})
+	} else {
+		v, err := fn(params)
+		res = NewRPCResponse(req.ID, v, err)
+	}
+	data, err := EncodeRPCMessage(res)
+	if err != nil {
+		return
+	}
+	h.PostMessage(string(data))
+}
+
+// Call 模拟页面调用绑定的 Go 函数 method 并等待结果，调用失败时返回 *RPCError，
+// ctx 结束时相当于页面通过 AbortSignal 取消了调用，流式返回的函数需要使用 Stream 调用
+func (h *Headless) Call(ctx context.Context, method string, params ...interface{}) (json.RawMessage, error) {
+	id, ch := h.newReply()
+	if err := h.postRequest(id, method, params); err != nil {
+		return nil, err
+	}
+	return h.waitReply(ctx, id, ch)
+}
+
+// Stream 模拟页面调用流式返回的 Go 函数 method，把收到的每个值传给 fn，
+// Go 函数结束后返回，fn 返回 false 时相当于页面停止迭代，会取消 Go 函数的执行
+func (h *Headless) Stream(ctx context.Context, method string, fn func(v json.RawMessage) bool, params ...interface{}) error {
+	id, ch := h.newReply()
+	ctx, cancel := context.WithCancel(ctx)
+	values := make(chan json.RawMessage, streamWindow)
+	h.m.Lock()
+	h.streams[id] = &headlessStream{values: values, ctx: ctx}
+	h.m.Unlock()
+	defer func() {
+		h.m.Lock()
+		delete(h.streams, id)
+		h.m.Unlock()
+		// 停止接收后还在发送的数据不再等待，已经收到的直接丢弃
+		cancel()
+		for len(values) > 0 {
+			<-values
+		}
+	}()
+
+	if err := h.postRequest(id, method, params); err != nil {
+		return err
+	}
+	h.postNotification("$pull", json.RawMessage(id), streamWindow)
+
+	for {
+		select {
+		case v := <-values:
+			if !fn(v) {
+				h.m.Lock()
+				delete(h.replies, id)
+				h.m.Unlock()
+				h.postNotification("$cancel", json.RawMessage(id))
+				return nil
+			}
+			h.postNotification("$pull", json.RawMessage(id), 1)
+		case res := <-ch:
+			// 结果和数据是按顺序发送的，结束前先把剩下的数据处理完
+			for len(values) > 0 {
+				fn(<-values)
+			}
+			if res.Error != nil {
+				return res.Error
+			}
+			return nil
+		case <-ctx.Done():
+			_, err := h.waitReply(ctx, id, ch)
+			return err
+		}
+	}
+}
+
+// headlessStream 是 Stream 正在接收的数据，ctx 在 Stream 返回时取消
+type headlessStream struct {
+	values chan json.RawMessage
+	ctx    context.Context
+}
+
+func (h *Headless) handleStream(n *RPCMessage) {
+	params, err := n.ParamList()
+	if err != nil || len(params) < 2 {
+		return
+	}
+	h.m.Lock()
+	st, ok := h.streams[string(params[0])]
+	h.m.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case st.values <- params[1]:
+	case <-st.ctx.Done():
+	}
+}
+
+func (h *Headless) newReply() (string, chan *RPCMessage) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.nextID++
+	id := jsString("js-" + strconv.FormatUint(h.nextID, 10))
+	ch := make(chan *RPCMessage, 1)
+	h.replies[id] = ch
+	return id, ch
+}
+
+func (h *Headless) postRequest(id string, method string, params []interface{}) error {
+	req, err := NewRPCRequest(json.RawMessage(id), method, params...)
+	if err != nil {
+		return err
+	}
+	data, err := EncodeRPCMessage(req)
+	if err != nil {
+		return err
+	}
+	h.PostMessage(string(data))
+	return nil
+}
+
+func (h *Headless) postNotification(method string, params ...interface{}) {
+	if n, err := NewRPCNotification(method, params...); err == nil {
+		if data, err := EncodeRPCMessage(n); err == nil {
+			h.PostMessage(string(data))
+		}
+	}
+}
+
+// waitReply 等待调用结果，ctx 结束时通知 Go 取消调用
+func (h *Headless) waitReply(ctx context.Context, id string, ch chan *RPCMessage) (json.RawMessage, error) {
+	select {
+	case res := <-ch:
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		return res.Result, nil
+	case <-ctx.Done():
+		h.m.Lock()
+		delete(h.replies, id)
+		h.m.Unlock()
+		h.postNotification("$cancel", json.RawMessage(id))
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,189 @@
+package webview2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type calculator struct{ base int }
+
+func (c *calculator) Add(n int) int                { return c.base + n }
+func (c *calculator) ParseURLPath(s string) string { return strings.Trim(s, "/") }
+
+func call(t *testing.T, h *Headless, method string, params ...interface{}) (json.RawMessage, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return h.Call(ctx, method, params...)
+}
+
+func TestHeadlessBindCall(t *testing.T) {
+	h := NewHeadless(WebViewOptions{})
+	h.Bind("add", func(a, b int) int { return a + b })
+	h.Bind("join", func(sep string, parts ...string) string { return strings.Join(parts, sep) })
+	h.Bind("ping", func() {})
+	if err := h.BindObject("calc", &calculator{base: 10}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method string
+		params []interface{}
+		want   string
+	}{
+		{"add", []interface{}{1, 2}, "3"},
+		{"join", []interface{}{"-", "a", "b", "c"}, `"a-b-c"`},
+		{"join", []interface{}{","}, `""`},
+		{"ping", nil, "null"},
+		{"calc.add", []interface{}{5}, "15"},
+		{"calc.parseURLPath", []interface{}{"/a/b/"}, `"a/b"`},
+	}
+	for _, tt := range tests {
+		got, err := call(t, h, tt.method, tt.params...)
+		if err != nil {
+			t.Errorf("%s: %v", tt.method, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s = %s, want %s", tt.method, got, tt.want)
+		}
+	}
+
+	if _, ok := h.Bindings()["calc.add"]; !ok {
+		t.Errorf("Bindings() = %v, missing calc.add", h.Bindings())
+	}
+	h.Unbind("calc")
+	if _, err := call(t, h, "calc.add", 1); rpcCode(err) != RPCMethodNotFound {
+		t.Errorf("call after Unbind: %v", err)
+	}
+}
+
+func TestHeadlessCallErrors(t *testing.T) {
+	h := NewHeadless(WebViewOptions{})
+	h.Bind("add", func(a, b int) int { return a + b })
+	h.Bind("fail", func() error { return errors.New("boom") })
+	h.Bind("custom", func() (int, error) { return 0, &RPCError{Code: 42, Message: "custom", Data: "detail"} })
+	h.Bind("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, BindTimeout(10*time.Millisecond))
+	h.Bind("admin", func() {}, BindOrigins("https://admin.example.com"))
+
+	tests := []struct {
+		name   string
+		method string
+		params []interface{}
+		code   int
+	}{
+		{"method not found", "missing", nil, RPCMethodNotFound},
+		{"too few params", "add", []interface{}{1}, RPCInvalidParams},
+		{"wrong param type", "add", []interface{}{"a", 1}, RPCInvalidParams},
+		{"function error", "fail", nil, RPCServerError},
+		{"rpc error", "custom", nil, 42},
+		{"timeout", "slow", nil, RPCCanceled},
+		{"origin not allowed", "admin", nil, RPCForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := call(t, h, tt.method, tt.params...)
+			if code := rpcCode(err); code != tt.code {
+				t.Errorf("code = %d (%v), want %d", code, err, tt.code)
+			}
+		})
+	}
+}
+
+func TestHeadlessInvalidMessage(t *testing.T) {
+	h := NewHeadless(WebViewOptions{})
+	h.PostMessage(`{"jsonrpc":"1.0","id":1,"method":"add"}`)
+	h.PostMessage(`{"jsonrpc":"2.0","id":2}`)
+	h.PostMessage(`not json`)
+
+	codes := map[string]int{}
+	for _, m := range h.Messages() {
+		if m.IsResponse() && m.Error != nil {
+			codes[string(m.ID)] = m.Error.Code
+		}
+	}
+	want := map[string]int{"1": RPCInvalidRequest, "2": RPCInvalidRequest}
+	if len(codes) != len(want) {
+		t.Fatalf("responses = %v, want %v", codes, want)
+	}
+	for id, code := range want {
+		if codes[id] != code {
+			t.Errorf("response %s code = %d, want %d", id, codes[id], code)
+		}
+	}
+}
+
+func TestHeadlessCancel(t *testing.T) {
+	h := NewHeadless(WebViewOptions{})
+	canceled := make(chan struct{})
+	h.Bind("wait", func(ctx context.Context) {
+		<-ctx.Done()
+		close(canceled)
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := h.Call(ctx, "wait"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Call = %v, want %v", err, context.DeadlineExceeded)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("Go function was not canceled")
+	}
+}
+
+func TestHeadlessEval(t *testing.T) {
+	h := NewHeadless(WebViewOptions{})
+	h.Eval("document.title = 'x'")
+	found := false
+	for _, js := range h.Evals() {
+		if js == "document.title = 'x'" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Evals() = %v, missing the evaluated script", h.Evals())
+	}
+
+	if _, err := h.EvalAsync("1 + 1"); rpcCode(err) != RPCMethodNotFound {
+		t.Errorf("EvalAsync without handler = %v", err)
+	}
+	h.HandleEval(func(js string) (interface{}, error) {
+		if js == "throw" {
+			return nil, errors.New("page error")
+		}
+		return len(js), nil
+	})
+	if v, err := h.EvalAsync("1 + 1"); err != nil || string(v) != "5" {
+		t.Errorf("EvalAsync = %s, %v", v, err)
+	}
+	if _, err := h.EvalAsync("throw"); rpcCode(err) != RPCServerError {
+		t.Errorf("EvalAsync(throw) = %v", err)
+	}
+
+	h.HandleCall("greet", func(params []json.RawMessage) (interface{}, error) {
+		var name string
+		_ = json.Unmarshal(params[0], &name)
+		return "hello " + name, nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if v, err := h.CallJS(ctx, "greet", "go"); err != nil || string(v) != `"hello go"` {
+		t.Errorf("CallJS = %s, %v", v, err)
+	}
+}
+
+func rpcCode(err error) int {
+	var e *RPCError
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return 0
+}
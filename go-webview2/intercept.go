@@ -0,0 +1,249 @@
+package webview2
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ResourceContext 是请求的资源类型，和 COREWEBVIEW2_WEB_RESOURCE_CONTEXT 一致
+type ResourceContext int
+
+const (
+	// ResourceAll 用于 Filter 中表示匹配所有类型
+	ResourceAll ResourceContext = iota
+	ResourceDocument
+	ResourceStylesheet
+	ResourceImage
+	ResourceMedia
+	ResourceFont
+	ResourceScript
+	ResourceXMLHTTPRequest
+	ResourceFetch
+	ResourceTextTrack
+	ResourceEventSource
+	ResourceWebSocket
+	ResourceManifest
+	ResourceSignedExchange
+	ResourcePing
+	ResourceCSPViolationReport
+	ResourceOther
+)
+
+// ResourceRequest 是页面发出的被拦截的请求，
+// 放行时对 Header 的修改会应用到实际发出的请求，可以用于注入认证信息等
+type ResourceRequest struct {
+	*http.Request
+	Context ResourceContext
+}
+
+type actionKind int
+
+const (
+	actionAllow actionKind = iota
+	actionBlock
+	actionRedirect
+	actionRespond
+)
+
+// Action 是 ResourceHandler 对请求的处理方式，通过 Allow、Block、Redirect、Respond 创建
+type Action struct {
+	kind     actionKind
+	location string
+	handler  http.Handler
+}
+
+// Allow 放行请求，由浏览器正常发出
+func Allow() Action { return Action{kind: actionAllow} }
+
+// Block 拦截请求，页面收到 403 响应
+func Block() Action { return Action{kind: actionBlock} }
+
+// Redirect 把请求重定向到 url，页面收到 307 响应
+func Redirect(url string) Action { return Action{kind: actionRedirect, location: url} }
+
+// Respond 由 h 返回响应，请求不会发到网络，可以用于模拟接口
+func Respond(h http.Handler) Action { return Action{kind: actionRespond, handler: h} }
+
+// String 返回处理方式的名称，如 allow、block
+func (a Action) String() string {
+	switch a.kind {
+	case actionBlock:
+		return "block"
+	case actionRedirect:
+		return "redirect"
+	case actionRespond:
+		return "respond"
+	default:
+		return "allow"
+	}
+}
+
+// ResourceHandler 决定如何处理拦截到的请求
+type ResourceHandler func(r *ResourceRequest) Action
+
+// ResourceMiddleware 包装 ResourceHandler，通过 Use 注册，
+// 先注册的在外层，最内层是 Handle 注册的处理，没有匹配的处理时放行
+type ResourceMiddleware func(next ResourceHandler) ResourceHandler
+
+// Filter 返回只处理匹配 pattern 和 ctx 的请求的中间件，其他请求交给下一个处理，
+// pattern 规则和 Handle 一致，ctx 为 ResourceAll 时匹配所有类型的请求
+//
+//	w.Use(webview2.Filter("*://tracker.example.com/*", webview2.ResourceAll, func(r *webview2.ResourceRequest) webview2.Action {
+//		return webview2.Block()
+//	}))
+func Filter(pattern string, ctx ResourceContext, h ResourceHandler) ResourceMiddleware {
+	return func(next ResourceHandler) ResourceHandler {
+		return func(r *ResourceRequest) Action {
+			if (ctx == ResourceAll || ctx == r.Context) && matchURIPattern(pattern, r.URL.String()) {
+				return h(r)
+			}
+			return next(r)
+		}
+	}
+}
+
+// RecordedRequest 是 Recorder 记录的一次请求
+type RecordedRequest struct {
+	Method  string
+	URL     string
+	Header  http.Header
+	Context ResourceContext
+	Action  Action
+}
+
+// Recorder 记录经过的请求和最终的处理方式，用于测试，
+// 通过 Use(recorder.Middleware) 注册，注册在最前面时记录所有中间件处理后的结果
+type Recorder struct {
+	m        sync.Mutex
+	requests []RecordedRequest
+}
+
+// NewRecorder 创建一个 Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Middleware 实现 ResourceMiddleware
+func (rec *Recorder) Middleware(next ResourceHandler) ResourceHandler {
+	return func(r *ResourceRequest) Action {
+		a := next(r)
+		rec.m.Lock()
+		rec.requests = append(rec.requests, RecordedRequest{
+			Method:  r.Method,
+			URL:     r.URL.String(),
+			Header:  r.Header.Clone(),
+			Context: r.Context,
+			Action:  a,
+		})
+		rec.m.Unlock()
+		return a
+	}
+}
+
+// Requests 返回记录的请求
+func (rec *Recorder) Requests() []RecordedRequest {
+	rec.m.Lock()
+	defer rec.m.Unlock()
+	return append([]RecordedRequest{}, rec.requests...)
+}
+
+// Reset 清空记录的请求
+func (rec *Recorder) Reset() {
+	rec.m.Lock()
+	defer rec.m.Unlock()
+	rec.requests = nil
+}
+
+// use 注册中间件，返回是否是第一个中间件，第一个中间件注册后需要拦截所有请求
+func (r *resourceRouter) use(mw ResourceMiddleware) bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.middlewares = append(r.middlewares, mw)
+	r.global++
+	return r.global == 1
+}
+
+// useFor 注册只需要拦截 patterns 中的地址的中间件，如离线缓存，
+// 中间件和 use 注册的中间件按注册顺序执行，但不会使所有请求都被拦截
+func (r *resourceRouter) useFor(mw ResourceMiddleware, patterns []string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.middlewares = append(r.middlewares, mw)
+	r.patterns = append(r.patterns, patterns...)
+}
+
+// intercepts 判断 uri 是否需要拦截，有 use 注册的中间件时拦截所有请求
+func (r *resourceRouter) intercepts(uri string) bool {
+	r.m.Lock()
+	all := r.global > 0
+	patterns := r.patterns
+	r.m.Unlock()
+	if all {
+		return true
+	}
+	for _, p := range patterns {
+		if matchURIPattern(p, uri) {
+			return true
+		}
+	}
+	return r.match(uri) != nil
+}
+
+// serve 依次经过中间件和 Handle 注册的处理，返回 nil 表示放行
+func (r *resourceRouter) serve(req *ResourceRequest) *resourceResponse {
+	r.m.Lock()
+	middlewares := append([]ResourceMiddleware{}, r.middlewares...)
+	r.m.Unlock()
+	h := func(req *ResourceRequest) Action {
+		if handler := r.match(req.URL.String()); handler != nil {
+			return Respond(handler)
+		}
+		return Allow()
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+
+	a := h(req)
+	switch a.kind {
+	case actionBlock:
+		res := newResourceResponse()
+		res.WriteHeader(http.StatusForbidden)
+		return res
+	case actionRedirect:
+		res := newResourceResponse()
+		res.Header().Set("Location", a.location)
+		res.WriteHeader(http.StatusTemporaryRedirect)
+		return res
+	case actionRespond:
+		return serveResource(a.handler, req.Request)
+	default:
+		return nil
+	}
+}
+
+// headerChanges 比较原始请求头和中间件修改后的请求头，返回需要设置和删除的请求头
+func headerChanges(orig [][2]string, header http.Header) (set [][2]string, del []string) {
+	before := http.Header{}
+	for _, h := range orig {
+		if http.CanonicalHeaderKey(h[0]) != "Host" {
+			before.Add(h[0], h[1])
+		}
+	}
+	for k := range before {
+		if _, ok := header[k]; !ok {
+			del = append(del, k)
+		}
+	}
+	for k, v := range header {
+		value := strings.Join(v, ", ")
+		if old, ok := before[k]; !ok || strings.Join(old, ", ") != value {
+			set = append(set, [2]string{k, value})
+		}
+	}
+	sort.Strings(del)
+	sort.Slice(set, func(i, j int) bool { return set[i][0] < set[j][0] })
+	return set, del
+}
@@ -24,49 +24,74 @@ var (
 	shlwapi                  = windows.NewLazySystemDLL("shlwapi")
 	shlwapiSHCreateMemStream = shlwapi.NewProc("SHCreateMemStream")
 
-	user32                   = windows.NewLazySystemDLL("user32")
-	User32LoadImageW         = user32.NewProc("LoadImageW")
-	User32GetSystemMetrics   = user32.NewProc("GetSystemMetrics")
-	User32RegisterClassExW   = user32.NewProc("RegisterClassExW")
-	User32CreateWindowExW    = user32.NewProc("CreateWindowExW")
-	User32DestroyWindow      = user32.NewProc("DestroyWindow")
-	User32ShowWindow         = user32.NewProc("ShowWindow")
-	User32UpdateWindow       = user32.NewProc("UpdateWindow")
-	User32SwitchToThisWindow = user32.NewProc("SwitchToThisWindow")
-	User32SetFocus           = user32.NewProc("SetFocus")
-	User32GetMessageW        = user32.NewProc("GetMessageW")
-	User32PeekMessageW       = user32.NewProc("PeekMessageW")
-	User32TranslateMessage   = user32.NewProc("TranslateMessage")
-	User32DispatchMessageW   = user32.NewProc("DispatchMessageW")
-	User32DefWindowProcW     = user32.NewProc("DefWindowProcW")
-	User32GetClientRect      = user32.NewProc("GetClientRect")
-	User32GetWindowRect      = user32.NewProc("GetWindowRect")
-	User32PostQuitMessage    = user32.NewProc("PostQuitMessage")
-	User32PostMessageW       = user32.NewProc("PostMessageW")
-	User32SetWindowTextW     = user32.NewProc("SetWindowTextW")
-	User32PostThreadMessageW = user32.NewProc("PostThreadMessageW")
-	User32GetWindowLongPtrW  = user32.NewProc("GetWindowLongPtrW")
-	User32SetWindowLongPtrW  = user32.NewProc("SetWindowLongPtrW")
-	User32AdjustWindowRect   = user32.NewProc("AdjustWindowRect")
-	User32SetWindowPos       = user32.NewProc("SetWindowPos")
-	User32IsDialogMessage    = user32.NewProc("IsDialogMessage")
-	User32GetAncestor        = user32.NewProc("GetAncestor")
-	User32ReleaseCapture     = user32.NewProc("ReleaseCapture")
-	User32SendMessage        = user32.NewProc("SendMessageW")
-	User32GetDpiForWindow    = user32.NewProc("GetDpiForWindow")
-	User32GetDC              = user32.NewProc("GetDC")
-	User32ReleaseDC          = user32.NewProc("ReleaseDC")
-)
-
-const (
-	SM_CXSCREEN = 0
-	SM_CYSCREEN = 1
+	user32                         = windows.NewLazySystemDLL("user32")
+	User32LoadImageW               = user32.NewProc("LoadImageW")
+	User32GetSystemMetrics         = user32.NewProc("GetSystemMetrics")
+	User32RegisterClassExW         = user32.NewProc("RegisterClassExW")
+	User32CreateWindowExW          = user32.NewProc("CreateWindowExW")
+	User32DestroyWindow            = user32.NewProc("DestroyWindow")
+	User32ShowWindow               = user32.NewProc("ShowWindow")
+	User32UpdateWindow             = user32.NewProc("UpdateWindow")
+	User32SwitchToThisWindow       = user32.NewProc("SwitchToThisWindow")
+	User32SetFocus                 = user32.NewProc("SetFocus")
+	User32GetMessageW              = user32.NewProc("GetMessageW")
+	User32PeekMessageW             = user32.NewProc("PeekMessageW")
+	User32TranslateMessage         = user32.NewProc("TranslateMessage")
+	User32DispatchMessageW         = user32.NewProc("DispatchMessageW")
+	User32DefWindowProcW           = user32.NewProc("DefWindowProcW")
+	User32GetClientRect            = user32.NewProc("GetClientRect")
+	User32GetWindowRect            = user32.NewProc("GetWindowRect")
+	User32PostQuitMessage          = user32.NewProc("PostQuitMessage")
+	User32PostMessageW             = user32.NewProc("PostMessageW")
+	User32SetWindowTextW           = user32.NewProc("SetWindowTextW")
+	User32PostThreadMessageW       = user32.NewProc("PostThreadMessageW")
+	User32GetWindowLongPtrW        = user32.NewProc("GetWindowLongPtrW")
+	User32SetWindowLongPtrW        = user32.NewProc("SetWindowLongPtrW")
+	User32AdjustWindowRect         = user32.NewProc("AdjustWindowRect")
+	User32SetWindowPos             = user32.NewProc("SetWindowPos")
+	User32IsDialogMessage          = user32.NewProc("IsDialogMessage")
+	User32GetAncestor              = user32.NewProc("GetAncestor")
+	User32ReleaseCapture           = user32.NewProc("ReleaseCapture")
+	User32SendMessage              = user32.NewProc("SendMessageW")
+	User32GetDpiForWindow          = user32.NewProc("GetDpiForWindow")
+	User32GetDC                    = user32.NewProc("GetDC")
+	User32ReleaseDC                = user32.NewProc("ReleaseDC")
+	User32EnableWindow             = user32.NewProc("EnableWindow")
+	User32GetWindowThreadProcessId = user32.NewProc("GetWindowThreadProcessId")
+	User32IsZoomed                 = user32.NewProc("IsZoomed")
+	User32IsIconic                 = user32.NewProc("IsIconic")
+	User32MonitorFromWindow        = user32.NewProc("MonitorFromWindow")
+	User32GetMonitorInfoW          = user32.NewProc("GetMonitorInfoW")
+	User32EnumDisplayMonitors      = user32.NewProc("EnumDisplayMonitors")
+	User32GetCursorPos             = user32.NewProc("GetCursorPos")
+	User32ScreenToClient           = user32.NewProc("ScreenToClient")
+)
+
+const (
+	SM_CXSCREEN       = 0
+	SM_CYSCREEN       = 1
+	SM_CXFRAME        = 32
+	SM_CYFRAME        = 33
+	SM_CXPADDEDBORDER = 92
 )
 
 const (
 	CW_USEDEFAULT = 0x80000000
 )
 
+const (
+	ColorWindow = 5
+)
+
+// HWNDMessage is HWND_MESSAGE, the parent of message-only windows
+const HWNDMessage = ^uintptr(2)
+
+// https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-setwindowpos
+const (
+	HWNDTopMost   = ^uintptr(0)
+	HWNDNoTopMost = ^uintptr(1)
+)
+
 const (
 	LR_DEFAULTCOLOR     = 0x0000
 	LR_MONOCHROME       = 0x0001
@@ -90,9 +115,11 @@ const (
 	SWMaximize = 2
 	SWShow     = 5
 	SWMinimize = 6
+	SWRestore  = 9
 )
 
 const (
+	SWPNoSize       = 0x0001
 	SWPNoZOrder     = 0x0004
 	SWPNoActivate   = 0x0010
 	SWPNoMove       = 0x0002
@@ -100,6 +127,7 @@ const (
 )
 
 const (
+	WMNull          = 0x0000
 	WMCreate        = 0x0001
 	WMDestroy       = 0x0002
 	WMMove          = 0x0003
@@ -108,6 +136,8 @@ const (
 	WMClose         = 0x0010
 	WMQuit          = 0x0012
 	WMGetMinMaxInfo = 0x0024
+	WMNCCalcSize    = 0x0083
+	WMNCHitTest     = 0x0084
 	WMNCLButtonDown = 0x00A1
 	WMMoving        = 0x0216
 	WMApp           = 0x8000
@@ -118,6 +148,21 @@ const (
 	WMDpiChanged    = 0x02E0
 )
 
+// https://learn.microsoft.com/en-us/windows/win32/inputdev/wm-nchittest
+const (
+	HTClient      = 1
+	HTCaption     = 2
+	HTMaxButton   = 9
+	HTLeft        = 10
+	HTRight       = 11
+	HTTop         = 12
+	HTTopLeft     = 13
+	HTTopRight    = 14
+	HTBottom      = 15
+	HTBottomLeft  = 16
+	HTBottomRight = 17
+)
+
 const (
 	GAParent    = 1
 	GARoot      = 2
@@ -143,6 +188,18 @@ const (
 	WSPopupWindow      = (WSPopup | WSBorder | WSSysMenu)
 )
 
+// https://learn.microsoft.com/en-us/windows/win32/winmsg/extended-window-styles
+const (
+	WSExTopMost = 0x00000008
+)
+
+// https://learn.microsoft.com/en-us/windows/win32/winmsg/wm-size
+const (
+	SizeRestored  = 0
+	SizeMinimized = 1
+	SizeMaximized = 2
+)
+
 const (
 	WAInactive    = 0
 	WAActive      = 1
@@ -171,6 +228,19 @@ type Rect struct {
 	Bottom int32
 }
 
+const (
+	MonitorDefaultToNearest = 0x00000002
+	MonitorInfoFPrimary     = 0x00000001
+)
+
+// https://learn.microsoft.com/en-us/windows/win32/api/winuser/ns-winuser-monitorinfo
+type MonitorInfo struct {
+	CbSize    uint32
+	RcMonitor Rect
+	RcWork    Rect
+	DwFlags   uint32
+}
+
 type MinMaxInfo struct {
 	PtReserved     Point
 	PtMaxSize      Point
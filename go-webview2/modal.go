@@ -0,0 +1,66 @@
+package webview2
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Owner 是可以作为其他窗口 WebViewOptions.Parent 的窗口，*Window 和 *Headless 实现了该接口
+type Owner interface {
+	// nativeHandle 返回系统的窗口句柄，没有系统窗口时为 0
+	nativeHandle() uintptr
+	// setEnabled 启用或者禁用窗口的用户输入，用于模态窗口，可以在任意线程调用
+	setEnabled(enabled bool)
+}
+
+// modalRuntime 提供 window.desktop.close，依赖 rpcRuntime
+const modalRuntime = `(function() {
+	var desktop = window.desktop = window.desktop || {};
+	desktop.close = function(result) {
+		window._rpc.notify("$close", [result === undefined ? null : result]);
+	};
+})()`
+
+// windowResult 记录窗口关闭时页面通过 window.desktop.close(result) 传入的值，用于 ShowModal
+type windowResult struct {
+	m      sync.Mutex
+	result json.RawMessage
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newWindowResult() *windowResult {
+	return &windowResult{done: make(chan struct{})}
+}
+
+// set 记录页面传入的值，窗口关闭后不再修改
+func (r *windowResult) set(result json.RawMessage) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	select {
+	case <-r.done:
+	default:
+		r.result = result
+	}
+}
+
+// closed 在窗口销毁后调用，ShowModal 返回
+func (r *windowResult) closed() {
+	r.once.Do(func() { close(r.done) })
+}
+
+// get 返回页面传入的值，没有传入或者传入 null 时返回 nil
+func (r *windowResult) get() json.RawMessage {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if string(r.result) == "null" {
+		return nil
+	}
+	return r.result
+}
+
+// wait 等待窗口关闭并返回页面传入的值
+func (r *windowResult) wait() json.RawMessage {
+	<-r.done
+	return r.get()
+}
@@ -0,0 +1,74 @@
+package webview2
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// showModal 在新的 goroutine 中调用 ShowModal，返回的 channel 收到 ShowModal 的返回值
+func showModal(h *Headless) <-chan json.RawMessage {
+	ch := make(chan json.RawMessage, 1)
+	go func() { ch <- h.ShowModal() }()
+	return ch
+}
+
+func modalResult(t *testing.T, ch <-chan json.RawMessage) json.RawMessage {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("ShowModal did not return")
+		return nil
+	}
+}
+
+func TestShowModalResult(t *testing.T) {
+	tests := []struct {
+		name  string
+		close func(h *Headless)
+		want  string
+	}{
+		{"close with a value", func(h *Headless) {
+			h.PostMessage(`{"jsonrpc":"2.0","method":"$close","params":[{"user":"admin"}]}`)
+		}, `{"user":"admin"}`},
+		{"close with null", func(h *Headless) {
+			h.PostMessage(`{"jsonrpc":"2.0","method":"$close","params":[null]}`)
+		}, ""},
+		{"close without params", func(h *Headless) {
+			h.PostMessage(`{"jsonrpc":"2.0","method":"$close"}`)
+		}, ""},
+		{"destroy", func(h *Headless) { h.Destroy() }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := NewHeadless(WebViewOptions{})
+			dialog := NewHeadless(WebViewOptions{Parent: parent, Modal: true, WindowOptions: WindowOptions{StartHidden: true}})
+			if parent.Enabled() {
+				t.Error("parent is enabled while the modal window is open")
+			}
+			result := showModal(dialog)
+			tt.close(dialog)
+			if got := modalResult(t, result); string(got) != tt.want {
+				t.Errorf("ShowModal = %s, want %s", got, tt.want)
+			}
+			if !parent.Enabled() {
+				t.Error("parent is disabled after the modal window closed")
+			}
+		})
+	}
+}
+
+func TestShowModalClosed(t *testing.T) {
+	// 窗口关闭后再调用 ShowModal 立即返回关闭时的值
+	h := NewHeadless(WebViewOptions{})
+	h.PostMessage(`{"jsonrpc":"2.0","method":"$close","params":[1]}`)
+	if got := modalResult(t, showModal(h)); string(got) != "1" {
+		t.Errorf("ShowModal = %s, want 1", got)
+	}
+	h.PostMessage(`{"jsonrpc":"2.0","method":"$close","params":[2]}`)
+	if got := modalResult(t, showModal(h)); string(got) != "1" {
+		t.Errorf("ShowModal after a second close = %s, want 1", got)
+	}
+}
@@ -0,0 +1,220 @@
+package webview2
+
+import "sync"
+
+// WebErrorStatus 是页面跳转失败的原因，和 COREWEBVIEW2_WEB_ERROR_STATUS 一致
+type WebErrorStatus int
+
+const (
+	WebErrorUnknown WebErrorStatus = iota
+	WebErrorCertificateCommonNameIsIncorrect
+	WebErrorCertificateExpired
+	WebErrorClientCertificateContainsErrors
+	WebErrorCertificateRevoked
+	WebErrorCertificateIsInvalid
+	WebErrorServerUnreachable
+	WebErrorTimeout
+	WebErrorHTTPInvalidServerResponse
+	WebErrorConnectionAborted
+	WebErrorConnectionReset
+	WebErrorDisconnected
+	WebErrorCannotConnect
+	WebErrorHostNameNotResolved
+	WebErrorOperationCanceled
+	WebErrorRedirectFailed
+	WebErrorUnexpectedError
+	WebErrorValidAuthenticationCredentialsRequired
+	WebErrorValidProxyAuthenticationRequired
+)
+
+var webErrorNames = []string{
+	"unknown",
+	"certificate_common_name_is_incorrect",
+	"certificate_expired",
+	"client_certificate_contains_errors",
+	"certificate_revoked",
+	"certificate_is_invalid",
+	"server_unreachable",
+	"timeout",
+	"http_invalid_server_response",
+	"connection_aborted",
+	"connection_reset",
+	"disconnected",
+	"cannot_connect",
+	"host_name_not_resolved",
+	"operation_canceled",
+	"redirect_failed",
+	"unexpected_error",
+	"valid_authentication_credentials_required",
+	"valid_proxy_authentication_required",
+}
+
+// String 返回小写下划线格式的名称，如 host_name_not_resolved
+func (s WebErrorStatus) String() string {
+	if s >= 0 && int(s) < len(webErrorNames) {
+		return webErrorNames[s]
+	}
+	return webErrorNames[WebErrorUnknown]
+}
+
+// NavigationStartingEvent 是页面开始跳转的事件，可以通过 Cancel 取消跳转
+type NavigationStartingEvent struct {
+	URL          string
+	NavigationID uint64
+	// IsUserInitiated 表示是否由用户操作发起，如点击链接
+	IsUserInitiated bool
+	// IsRedirected 表示是否是重定向
+	IsRedirected bool
+
+	canceled bool
+}
+
+// Cancel 取消这次跳转
+func (e *NavigationStartingEvent) Cancel() {
+	e.canceled = true
+}
+
+// Canceled 返回这次跳转是否已经被取消
+func (e *NavigationStartingEvent) Canceled() bool {
+	return e.canceled
+}
+
+// NavigationCompletedEvent 是页面跳转完成的事件，跳转失败时 Success 为 false
+type NavigationCompletedEvent struct {
+	// URL 是开始跳转时的地址
+	URL          string
+	NavigationID uint64
+	Success      bool
+	// WebErrorStatus 是跳转失败的原因
+	WebErrorStatus WebErrorStatus
+	// HTTPStatus 是页面的 HTTP 状态码，WebView2 Runtime 不支持或者没有收到响应时为 0
+	HTTPStatus int
+}
+
+// SourceChangedEvent 是页面地址变化的事件，包括跳转和 history.pushState 等
+type SourceChangedEvent struct {
+	URL string
+	// IsNewDocument 表示是否打开了新的页面，在同一个页面中修改地址时为 false
+	IsNewDocument bool
+}
+
+// DOMContentLoadedEvent 是页面 DOMContentLoaded 的事件
+type DOMContentLoadedEvent struct {
+	NavigationID uint64
+}
+
+// navigationListener 是通过 OnNavigationStarting 等注册的监听，fn 为对应事件的函数
+type navigationListener struct {
+	fn interface{}
+}
+
+// navigationEvents 管理页面跳转相关事件的监听，监听函数在 UI 线程中按注册顺序执行
+type navigationEvents struct {
+	navm      sync.Mutex
+	starting  []*navigationListener
+	completed []*navigationListener
+	source    []*navigationListener
+	loaded    []*navigationListener
+	title     []*navigationListener
+	// urls 是还没完成的跳转的地址，key 为 NavigationID，用于 NavigationCompletedEvent.URL
+	urls map[uint64]string
+}
+
+func (n *navigationEvents) add(list *[]*navigationListener, fn interface{}) func() {
+	l := &navigationListener{fn}
+	n.navm.Lock()
+	*list = append(*list, l)
+	n.navm.Unlock()
+	return func() {
+		n.navm.Lock()
+		defer n.navm.Unlock()
+		for i, v := range *list {
+			if v == l {
+				*list = append((*list)[:i:i], (*list)[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (n *navigationEvents) get(list *[]*navigationListener) []*navigationListener {
+	n.navm.Lock()
+	defer n.navm.Unlock()
+	return append([]*navigationListener{}, *list...)
+}
+
+// OnNavigationStarting 监听页面开始跳转，fn 中可以调用 e.Cancel() 取消跳转，返回取消监听的函数，
+// fn 在 UI 线程中执行，不能阻塞
+func (n *navigationEvents) OnNavigationStarting(fn func(e *NavigationStartingEvent)) func() {
+	return n.add(&n.starting, fn)
+}
+
+// OnNavigationCompleted 监听页面跳转完成，包括跳转失败，返回取消监听的函数，
+// fn 在 UI 线程中执行，不能阻塞
+func (n *navigationEvents) OnNavigationCompleted(fn func(e *NavigationCompletedEvent)) func() {
+	return n.add(&n.completed, fn)
+}
+
+// OnSourceChanged 监听页面地址变化，返回取消监听的函数，fn 在 UI 线程中执行，不能阻塞
+func (n *navigationEvents) OnSourceChanged(fn func(e *SourceChangedEvent)) func() {
+	return n.add(&n.source, fn)
+}
+
+// OnDOMContentLoaded 监听页面 DOMContentLoaded，需要 WebView2 Runtime 支持 ICoreWebView2_2，
+// 返回取消监听的函数，fn 在 UI 线程中执行，不能阻塞
+func (n *navigationEvents) OnDOMContentLoaded(fn func(e *DOMContentLoadedEvent)) func() {
+	return n.add(&n.loaded, fn)
+}
+
+// OnTitleChanged 监听页面标题（document.title）变化，返回取消监听的函数，
+// fn 在 UI 线程中执行，不能阻塞
+func (n *navigationEvents) OnTitleChanged(fn func(title string)) func() {
+	return n.add(&n.title, fn)
+}
+
+// navigationStarting 通知页面开始跳转，返回跳转是否被取消
+func (n *navigationEvents) navigationStarting(e *NavigationStartingEvent) bool {
+	n.navm.Lock()
+	if n.urls == nil {
+		n.urls = map[uint64]string{}
+	}
+	if _, ok := n.urls[e.NavigationID]; !ok {
+		// 重定向时 NavigationID 不变，保留最开始的地址
+		n.urls[e.NavigationID] = e.URL
+	}
+	n.navm.Unlock()
+	for _, l := range n.get(&n.starting) {
+		l.fn.(func(*NavigationStartingEvent))(e)
+	}
+	return e.canceled
+}
+
+func (n *navigationEvents) navigationCompleted(e *NavigationCompletedEvent) {
+	n.navm.Lock()
+	if url, ok := n.urls[e.NavigationID]; ok {
+		e.URL = url
+		delete(n.urls, e.NavigationID)
+	}
+	n.navm.Unlock()
+	for _, l := range n.get(&n.completed) {
+		l.fn.(func(*NavigationCompletedEvent))(e)
+	}
+}
+
+func (n *navigationEvents) sourceChanged(e *SourceChangedEvent) {
+	for _, l := range n.get(&n.source) {
+		l.fn.(func(*SourceChangedEvent))(e)
+	}
+}
+
+func (n *navigationEvents) domContentLoaded(e *DOMContentLoadedEvent) {
+	for _, l := range n.get(&n.loaded) {
+		l.fn.(func(*DOMContentLoadedEvent))(e)
+	}
+}
+
+func (n *navigationEvents) titleChanged(title string) {
+	for _, l := range n.get(&n.title) {
+		l.fn.(func(string))(title)
+	}
+}
@@ -0,0 +1,96 @@
+package webview2
+
+import (
+	"io/fs"
+	"net/http"
+	"unsafe"
+)
+
+type logger interface {
+	Info(v ...interface{})
+}
+
+type WindowOptions struct {
+	Title  string
+	Width  uint
+	Height uint
+	IconId uint
+	Icon   string
+	Center bool
+	// Frameless 为 true 时去掉系统的标题栏和边框，页面中 -webkit-app-region: drag 的区域作为标题栏，
+	// 可以拖动、双击最大化以及拖到屏幕边缘分屏，data-app-region="maximize" 的元素作为最大化按钮
+	Frameless bool
+	// ResizeBorder 是无边框窗口边缘可以调整大小的宽度，单位为逻辑像素，为 0 时默认为 6，小于 0 时不能通过边缘调整大小。
+	// 窗口没有最大化时页面会向内缩进这个宽度，边缘由窗口自己处理
+	ResizeBorder int
+	// AlwaysOnTop 为 true 时窗口总是显示在其他窗口上面
+	AlwaysOnTop bool
+	// StartHidden 为 true 时创建后不显示窗口，WebView2 仍然会完成初始化并打开页面，之后调用 Show 立即显示
+	StartHidden bool
+}
+
+type WebViewOptions struct {
+	Window            unsafe.Pointer
+	StartURL          string
+	FallbackPage      string
+	StartHTML         string
+	HideWindowOnClose bool
+
+	// if true, enable context menu and chrome devtools
+	Debug bool
+
+	// DataPath specifies the datapath for the WebView2 runtime to use for the
+	// browser instance.
+	DataPath string
+
+	// AutoFocus will try to keep the WebView2 widget focused when the window
+	// is focused.
+	AutoFocus bool
+
+	Logger logger
+
+	// Assets 是页面的静态文件，如 embed.FS，通过 AppOrigin（https://app.local/）访问，
+	// 没有设置 StartURL 和 StartHTML 时默认打开 https://app.local/
+	Assets fs.FS
+
+	// Handler 处理发往 AppOrigin 的请求，和 Assets 同时设置时使用 Handler，
+	// 可以用 AssetsHandler(assets) 作为其中的静态文件服务
+	Handler http.Handler
+
+	// VirtualHosts 把 https 主机名映射到本地目录，key 为主机名，如 app.example，
+	// 页面可以通过 https://app.example/ 访问目录中的文件
+	VirtualHosts map[string]VirtualHost
+
+	// AllowedOrigins 是默认允许调用绑定函数的页面地址，格式见 BindOrigins，
+	// 为 nil 时允许所有页面调用
+	AllowedOrigins []string
+
+	// Cache 开启离线缓存，匹配规则的请求的响应会缓存到本地，网络不可用时使用缓存，
+	// 为 nil 时不缓存
+	Cache *CacheOptions
+
+	// NavigationPolicy 限制页面可以跳转的地址，不允许的地址在系统默认浏览器中打开，为 nil 时不限制
+	NavigationPolicy *NavigationPolicy
+
+	// Fallback 是页面跳转失败时显示的错误页面和自动重试的配置，设置后忽略 FallbackPage，
+	// FallbackPage 相当于只设置了 Fallback.Page
+	Fallback *FallbackOptions
+
+	// Parent 是窗口的所有者，窗口总是显示在 Parent 上面，Parent 最小化或者关闭时一起最小化或者关闭
+	Parent Owner
+
+	// Modal 为 true 时是模态窗口，窗口打开期间禁用 Parent，通过 ShowModal 等待窗口关闭
+	Modal bool
+
+	// PersistState 为 true 时窗口关闭时把位置、大小、最大化和全屏状态保存到 DataPath 下的 WindowState.json，
+	// 下次创建时恢复，保存的位置不在当前连接的显示器上时会移到显示器中。
+	// 通过 App 创建的窗口按窗口 id 分别保存，其他窗口的 id 为 main
+	PersistState bool
+
+	// id 是 App 中窗口的 id，用于 PersistState
+	id string
+
+	// WindowOptions customizes the window that is created to embed the
+	// WebView2 widget.
+	WindowOptions WindowOptions
+}
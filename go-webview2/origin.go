@@ -0,0 +1,79 @@
+package webview2
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// BindOrigins 设置允许调用绑定函数的页面地址，没有设置时使用 WebViewOptions.AllowedOrigins，
+// 都没有设置时允许所有页面调用。其他页面调用时 JS 收到 RPCForbidden 错误。
+//
+// pattern 可以是:
+//   - 页面来源，如 https://app.local、http://localhost:8080，host 可以使用通配符，如 https://*.example.com
+//   - 带路径的地址，路径以 * 结尾时匹配该前缀下的所有页面，如 https://example.com/admin/*
+//   - about:blank（SetHtml 打开的页面）等不带 host 的地址，需要完全一致
+//   - *，允许所有页面
+func BindOrigins(patterns ...string) BindOption {
+	return func(o *bindOptions) {
+		o.origins = append(o.origins, patterns...)
+	}
+}
+
+// allowOrigin 判断 source 是否匹配 patterns 中的任意一个，patterns 为 nil 时允许所有页面
+func allowOrigin(patterns []string, source string) bool {
+	if patterns == nil {
+		return true
+	}
+	for _, p := range patterns {
+		if matchOrigin(p, source) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchOrigin(pattern, source string) bool {
+	if pattern == "*" {
+		return true
+	}
+	p, err := url.Parse(pattern)
+	if err != nil {
+		return false
+	}
+	s, err := url.Parse(source)
+	if err != nil {
+		return false
+	}
+	if p.Host == "" {
+		return pattern == source
+	}
+	if !strings.EqualFold(p.Scheme, s.Scheme) {
+		return false
+	}
+	// host 中的 * 匹配任意字符，没有指定端口时只匹配不带端口的地址
+	if ok, _ := path.Match(strings.ToLower(p.Host), strings.ToLower(s.Host)); !ok {
+		return false
+	}
+	switch {
+	case p.Path == "" || p.Path == "/*":
+		return true
+	case strings.HasSuffix(p.Path, "*"):
+		return strings.HasPrefix(s.Path, strings.TrimSuffix(p.Path, "*"))
+	default:
+		return p.Path == s.Path
+	}
+}
+
+// sameOrigin 判断 a 和 b 是否是同一个来源的页面，about:blank 等不带 host 的地址需要完全一致
+func sameOrigin(a, b string) bool {
+	x, err := url.Parse(a)
+	if err != nil || x.Host == "" {
+		return a == b
+	}
+	y, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(x.Scheme, y.Scheme) && strings.EqualFold(x.Host, y.Host)
+}
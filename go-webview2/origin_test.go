@@ -0,0 +1,136 @@
+package webview2
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		pattern string
+		source  string
+		want    bool
+	}{
+		{"*", "https://example.com/page", true},
+		{"*", "about:blank", true},
+		{"https://app.local", "https://app.local/index.html", true},
+		{"https://app.local", "https://app.local", true},
+		{"https://app.local", "http://app.local/", false},
+		{"https://app.local", "https://app.local.evil.com/", false},
+		{"https://APP.local", "https://app.LOCAL/", true},
+		{"http://localhost:8080", "http://localhost:8080/", true},
+		{"http://localhost:8080", "http://localhost:3000/", false},
+		{"http://localhost", "http://localhost:8080/", false},
+		{"https://*.example.com", "https://a.example.com/x", true},
+		{"https://*.example.com", "https://example.com/x", false},
+		{"https://*.example.com", "https://a.example.com.evil.com/", false},
+		{"https://example.com/admin/*", "https://example.com/admin/users", true},
+		{"https://example.com/admin/*", "https://example.com/admin/", true},
+		{"https://example.com/admin/*", "https://example.com/public", false},
+		{"https://example.com/admin", "https://example.com/admin", true},
+		{"https://example.com/admin", "https://example.com/admin/users", false},
+		{"https://example.com/*", "https://example.com/anything", true},
+		{"about:blank", "about:blank", true},
+		{"about:blank", "about:srcdoc", false},
+		{"https://example.com", "::", false},
+	}
+	for _, tt := range tests {
+		if got := matchOrigin(tt.pattern, tt.source); got != tt.want {
+			t.Errorf("matchOrigin(%q, %q) = %v, want %v", tt.pattern, tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestAllowOrigin(t *testing.T) {
+	if !allowOrigin(nil, "https://any.example.com/") {
+		t.Error("nil patterns should allow every page")
+	}
+	if allowOrigin([]string{}, "https://any.example.com/") {
+		t.Error("empty patterns should allow no page")
+	}
+	patterns := []string{"https://app.local", "http://localhost:5173"}
+	for source, want := range map[string]bool{
+		"https://app.local/":     true,
+		"http://localhost:5173/": true,
+		"https://evil.com/":      false,
+	} {
+		if got := allowOrigin(patterns, source); got != want {
+			t.Errorf("allowOrigin(%q) = %v, want %v", source, got, want)
+		}
+	}
+}
+
+func TestSameOrigin(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"https://app.local/index.html", "https://app.local/other", true},
+		{"https://APP.local/", "https://app.local/", true},
+		{"https://app.local/", "http://app.local/", false},
+		{"https://app.local/", "https://app.local:8443/", false},
+		{"https://app.local/", "https://evil.com/", false},
+		{"about:blank", "about:blank", true},
+		{"about:blank", "https://app.local/", false},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		if got := sameOrigin(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameOrigin(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// 错误页面是 SetHtml 打开的 about:blank，不在 AllowedOrigins 中也可以调用 window.desktop.retry()
+func TestInternalPageRetry(t *testing.T) {
+	h := NewHeadless(WebViewOptions{
+		AllowedOrigins: []string{"https://app.local"},
+		Fallback:       &FallbackOptions{Page: "failed"},
+	})
+	h.NavigateError("https://app.local/", WebErrorHostNameNotResolved, 0)
+	if h.URL() != "about:blank" {
+		t.Fatalf("URL() = %q, want the fallback page", h.URL())
+	}
+	h.PostMessage(`{"jsonrpc":"2.0","method":"$retry","params":[]}`)
+	if got := h.Navigations(); len(got) != 1 || got[0] != "https://app.local/" {
+		t.Errorf("Navigations() = %v, want the failed url", got)
+	}
+
+	// 其他页面不能调用内部方法
+	h.PostMessageFrom("https://evil.com/", `{"jsonrpc":"2.0","method":"$retry","params":[]}`)
+	if got := h.Navigations(); len(got) != 1 {
+		t.Errorf("Navigations() = %v after $retry from another origin", got)
+	}
+}
+
+// 只接受和发送请求时同一个来源的页面的响应
+func TestResponseOrigin(t *testing.T) {
+	wb := &memoryBrowser{}
+	b := newBridge(wb, func(f func()) { f() }, discardLogger{})
+	defer b.close()
+	b.setPage("https://app.local/")
+	result := make(chan json.RawMessage, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		v, _ := b.evalAsync(ctx, "1 + 1")
+		result <- v
+	}()
+	for i := 0; i < 100; i++ {
+		wb.m.Lock()
+		sent := len(wb.evals)
+		wb.m.Unlock()
+		if sent > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	b.msgcb(`{"jsonrpc":"2.0","id":"go-1","result":"forged"}`, "https://evil.com/")
+	b.msgcb(`{"jsonrpc":"2.0","id":"go-1","result":2}`, "https://app.local/other")
+	if v := <-result; string(v) != "2" {
+		t.Errorf("evalAsync = %s, want 2", v)
+	}
+}
@@ -0,0 +1,28 @@
+package edge
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type _ICoreWebView2DOMContentLoadedEventArgsVtbl struct {
+	_IUnknownVtbl
+	GetNavigationId ComProc
+}
+
+type ICoreWebView2DOMContentLoadedEventArgs struct {
+	vtbl *_ICoreWebView2DOMContentLoadedEventArgsVtbl
+}
+
+func (i *ICoreWebView2DOMContentLoadedEventArgs) GetNavigationId() (uint64, error) {
+	var id uint64
+	_, _, err := i.vtbl.GetNavigationId.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&id)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return 0, err
+	}
+	return id, nil
+}
@@ -0,0 +1,162 @@
+package edge
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type _ICoreWebView2HttpRequestHeadersVtbl struct {
+	_IUnknownVtbl
+	GetHeader    ComProc
+	GetHeaders   ComProc
+	Contains     ComProc
+	SetHeader    ComProc
+	RemoveHeader ComProc
+	GetIterator  ComProc
+}
+
+type ICoreWebView2HttpRequestHeaders struct {
+	vtbl *_ICoreWebView2HttpRequestHeadersVtbl
+}
+
+func (i *ICoreWebView2HttpRequestHeaders) Release() uintptr {
+	r, _, _ := i.vtbl.Release.Call(uintptr(unsafe.Pointer(i)))
+	return r
+}
+
+func (i *ICoreWebView2HttpRequestHeaders) SetHeader(name, value string) error {
+	_name, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return err
+	}
+	_value, err := windows.UTF16PtrFromString(value)
+	if err != nil {
+		return err
+	}
+	_, _, err = i.vtbl.SetHeader.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(_name)),
+		uintptr(unsafe.Pointer(_value)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return err
+	}
+	return nil
+}
+
+func (i *ICoreWebView2HttpRequestHeaders) RemoveHeader(name string) error {
+	_name, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return err
+	}
+	_, _, err = i.vtbl.RemoveHeader.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(_name)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return err
+	}
+	return nil
+}
+
+func (i *ICoreWebView2HttpRequestHeaders) GetIterator() (*ICoreWebView2HttpHeadersCollectionIterator, error) {
+	var iterator *ICoreWebView2HttpHeadersCollectionIterator
+	_, _, err := i.vtbl.GetIterator.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&iterator)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return nil, err
+	}
+	return iterator, nil
+}
+
+// All returns all headers as name/value pairs, in the order of the iterator.
+func (i *ICoreWebView2HttpRequestHeaders) All() ([][2]string, error) {
+	it, err := i.GetIterator()
+	if err != nil {
+		return nil, err
+	}
+	defer it.Release()
+	return it.all()
+}
+
+// all reads the remaining headers of the iterator.
+func (it *ICoreWebView2HttpHeadersCollectionIterator) all() ([][2]string, error) {
+	headers := [][2]string{}
+	for {
+		has, err := it.HasCurrentHeader()
+		if err != nil {
+			return nil, err
+		}
+		if !has {
+			return headers, nil
+		}
+		name, value, err := it.GetCurrentHeader()
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, [2]string{name, value})
+		if _, err := it.MoveNext(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+type _ICoreWebView2HttpHeadersCollectionIteratorVtbl struct {
+	_IUnknownVtbl
+	GetCurrentHeader    ComProc
+	GetHasCurrentHeader ComProc
+	MoveNext            ComProc
+}
+
+type ICoreWebView2HttpHeadersCollectionIterator struct {
+	vtbl *_ICoreWebView2HttpHeadersCollectionIteratorVtbl
+}
+
+func (i *ICoreWebView2HttpHeadersCollectionIterator) Release() uintptr {
+	r, _, _ := i.vtbl.Release.Call(uintptr(unsafe.Pointer(i)))
+	return r
+}
+
+func (i *ICoreWebView2HttpHeadersCollectionIterator) GetCurrentHeader() (string, string, error) {
+	var _name, _value *uint16
+	_, _, err := i.vtbl.GetCurrentHeader.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&_name)),
+		uintptr(unsafe.Pointer(&_value)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return "", "", err
+	}
+	name := windows.UTF16PtrToString(_name)
+	value := windows.UTF16PtrToString(_value)
+	windows.CoTaskMemFree(unsafe.Pointer(_name))
+	windows.CoTaskMemFree(unsafe.Pointer(_value))
+	return name, value, nil
+}
+
+func (i *ICoreWebView2HttpHeadersCollectionIterator) HasCurrentHeader() (bool, error) {
+	var has int32
+	_, _, err := i.vtbl.GetHasCurrentHeader.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&has)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return false, err
+	}
+	return has != 0, nil
+}
+
+func (i *ICoreWebView2HttpHeadersCollectionIterator) MoveNext() (bool, error) {
+	var has int32
+	_, _, err := i.vtbl.MoveNext.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&has)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return false, err
+	}
+	return has != 0, nil
+}
@@ -34,3 +34,67 @@ func (i *ICoreWebView2NavigationCompletedEventArgs) IsSuccess() bool {
 	}
 	return enabled
 }
+
+func (i *ICoreWebView2NavigationCompletedEventArgs) GetWebErrorStatus() (COREWEBVIEW2_WEB_ERROR_STATUS, error) {
+	var status COREWEBVIEW2_WEB_ERROR_STATUS
+	_, _, err := i.vtbl.GetWebErrorStatus.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&status)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return 0, err
+	}
+	return status, nil
+}
+
+func (i *ICoreWebView2NavigationCompletedEventArgs) GetNavigationId() (uint64, error) {
+	var id uint64
+	_, _, err := i.vtbl.GetNavigationId.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&id)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return 0, err
+	}
+	return id, nil
+}
+
+type _ICoreWebView2NavigationCompletedEventArgs2Vtbl struct {
+	_ICoreWebView2NavigationCompletedEventArgsVtbl
+	GetHttpStatusCode ComProc
+}
+
+type ICoreWebView2NavigationCompletedEventArgs2 struct {
+	vtbl *_ICoreWebView2NavigationCompletedEventArgs2Vtbl
+}
+
+// GetICoreWebView2NavigationCompletedEventArgs2 returns nil when the installed
+// WebView2 Runtime doesn't support it.
+func (i *ICoreWebView2NavigationCompletedEventArgs) GetICoreWebView2NavigationCompletedEventArgs2() *ICoreWebView2NavigationCompletedEventArgs2 {
+	var result *ICoreWebView2NavigationCompletedEventArgs2
+
+	iidICoreWebView2NavigationCompletedEventArgs2 := NewGUID("{FDF8B738-EE1E-4DB2-A329-8D7D7B74D792}")
+	_, _, _ = i.vtbl.QueryInterface.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(iidICoreWebView2NavigationCompletedEventArgs2)),
+		uintptr(unsafe.Pointer(&result)))
+
+	return result
+}
+
+func (i *ICoreWebView2NavigationCompletedEventArgs2) Release() uintptr {
+	r, _, _ := i.vtbl.Release.Call(uintptr(unsafe.Pointer(i)))
+	return r
+}
+
+func (i *ICoreWebView2NavigationCompletedEventArgs2) GetHttpStatusCode() (int, error) {
+	var code int32
+	_, _, err := i.vtbl.GetHttpStatusCode.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&code)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return 0, err
+	}
+	return int(code), nil
+}
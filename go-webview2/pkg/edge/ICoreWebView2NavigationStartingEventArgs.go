@@ -0,0 +1,117 @@
+package edge
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type _ICoreWebView2NavigationStartingEventArgsVtbl struct {
+	_IUnknownVtbl
+	GetUri             ComProc
+	GetIsUserInitiated ComProc
+	GetIsRedirected    ComProc
+	GetRequestHeaders  ComProc
+	GetCancel          ComProc
+	PutCancel          ComProc
+	GetNavigationId    ComProc
+}
+
+type ICoreWebView2NavigationStartingEventArgs struct {
+	vtbl *_ICoreWebView2NavigationStartingEventArgsVtbl
+}
+
+func (i *ICoreWebView2NavigationStartingEventArgs) AddRef() uintptr {
+	r, _, _ := i.vtbl.AddRef.Call(uintptr(unsafe.Pointer(i)))
+	return r
+}
+
+func (i *ICoreWebView2NavigationStartingEventArgs) Release() uintptr {
+	r, _, _ := i.vtbl.Release.Call(uintptr(unsafe.Pointer(i)))
+	return r
+}
+
+func (i *ICoreWebView2NavigationStartingEventArgs) GetUri() (string, error) {
+	var _uri *uint16
+	_, _, err := i.vtbl.GetUri.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&_uri)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return "", err
+	}
+	uri := windows.UTF16PtrToString(_uri)
+	windows.CoTaskMemFree(unsafe.Pointer(_uri))
+	return uri, nil
+}
+
+func (i *ICoreWebView2NavigationStartingEventArgs) GetIsUserInitiated() (bool, error) {
+	var value int32
+	_, _, err := i.vtbl.GetIsUserInitiated.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&value)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return false, err
+	}
+	return value != 0, nil
+}
+
+func (i *ICoreWebView2NavigationStartingEventArgs) GetIsRedirected() (bool, error) {
+	var value int32
+	_, _, err := i.vtbl.GetIsRedirected.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&value)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return false, err
+	}
+	return value != 0, nil
+}
+
+func (i *ICoreWebView2NavigationStartingEventArgs) GetRequestHeaders() (*ICoreWebView2HttpRequestHeaders, error) {
+	var headers *ICoreWebView2HttpRequestHeaders
+	_, _, err := i.vtbl.GetRequestHeaders.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&headers)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return nil, err
+	}
+	return headers, nil
+}
+
+func (i *ICoreWebView2NavigationStartingEventArgs) GetCancel() (bool, error) {
+	var value int32
+	_, _, err := i.vtbl.GetCancel.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&value)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return false, err
+	}
+	return value != 0, nil
+}
+
+func (i *ICoreWebView2NavigationStartingEventArgs) PutCancel(cancel bool) error {
+	_, _, err := i.vtbl.PutCancel.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(boolToInt(cancel)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return err
+	}
+	return nil
+}
+
+func (i *ICoreWebView2NavigationStartingEventArgs) GetNavigationId() (uint64, error) {
+	var id uint64
+	_, _, err := i.vtbl.GetNavigationId.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&id)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return 0, err
+	}
+	return id, nil
+}
@@ -0,0 +1,60 @@
+package edge
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type _ICoreWebView2NewWindowRequestedEventArgsVtbl struct {
+	_IUnknownVtbl
+	GetUri             ComProc
+	PutNewWindow       ComProc
+	GetNewWindow       ComProc
+	PutHandled         ComProc
+	GetHandled         ComProc
+	GetIsUserInitiated ComProc
+	GetDeferral        ComProc
+	GetWindowFeatures  ComProc
+}
+
+type ICoreWebView2NewWindowRequestedEventArgs struct {
+	vtbl *_ICoreWebView2NewWindowRequestedEventArgsVtbl
+}
+
+func (i *ICoreWebView2NewWindowRequestedEventArgs) GetUri() (string, error) {
+	var _uri *uint16
+	_, _, err := i.vtbl.GetUri.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&_uri)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return "", err
+	}
+	uri := windows.UTF16PtrToString(_uri)
+	windows.CoTaskMemFree(unsafe.Pointer(_uri))
+	return uri, nil
+}
+
+func (i *ICoreWebView2NewWindowRequestedEventArgs) PutHandled(handled bool) error {
+	_, _, err := i.vtbl.PutHandled.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(boolToInt(handled)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return err
+	}
+	return nil
+}
+
+func (i *ICoreWebView2NewWindowRequestedEventArgs) GetIsUserInitiated() (bool, error) {
+	var value int32
+	_, _, err := i.vtbl.GetIsUserInitiated.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&value)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return false, err
+	}
+	return value != 0, nil
+}
@@ -0,0 +1,28 @@
+package edge
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type _ICoreWebView2SourceChangedEventArgsVtbl struct {
+	_IUnknownVtbl
+	GetIsNewDocument ComProc
+}
+
+type ICoreWebView2SourceChangedEventArgs struct {
+	vtbl *_ICoreWebView2SourceChangedEventArgsVtbl
+}
+
+func (i *ICoreWebView2SourceChangedEventArgs) GetIsNewDocument() (bool, error) {
+	var value int32
+	_, _, err := i.vtbl.GetIsNewDocument.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&value)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return false, err
+	}
+	return value != 0, nil
+}
@@ -22,7 +22,12 @@ type ICoreWebView2WebResourceRequest struct {
 }
 
 func (i *ICoreWebView2WebResourceRequest) AddRef() uintptr {
-	r, _, _ := i.vtbl.AddRef.Call()
+	r, _, _ := i.vtbl.AddRef.Call(uintptr(unsafe.Pointer(i)))
+	return r
+}
+
+func (i *ICoreWebView2WebResourceRequest) Release() uintptr {
+	r, _, _ := i.vtbl.Release.Call(uintptr(unsafe.Pointer(i)))
 	return r
 }
 
@@ -41,3 +46,42 @@ func (i *ICoreWebView2WebResourceRequest) GetUri() (string, error) {
 	windows.CoTaskMemFree(unsafe.Pointer(_uri))
 	return uri, nil
 }
+
+func (i *ICoreWebView2WebResourceRequest) GetMethod() (string, error) {
+	var _method *uint16
+	_, _, err := i.vtbl.GetMethod.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&_method)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return "", err
+	}
+	method := windows.UTF16PtrToString(_method)
+	windows.CoTaskMemFree(unsafe.Pointer(_method))
+	return method, nil
+}
+
+// GetContent returns the request body, it is nil if the request has no body.
+func (i *ICoreWebView2WebResourceRequest) GetContent() (*IStream, error) {
+	var stream *IStream
+	_, _, err := i.vtbl.GetContent.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&stream)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return nil, err
+	}
+	return stream, nil
+}
+
+func (i *ICoreWebView2WebResourceRequest) GetHeaders() (*ICoreWebView2HttpRequestHeaders, error) {
+	var headers *ICoreWebView2HttpRequestHeaders
+	_, _, err := i.vtbl.GetHeaders.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&headers)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return nil, err
+	}
+	return headers, nil
+}
@@ -0,0 +1,43 @@
+package edge
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type _ICoreWebView2WebResourceResponseReceivedEventArgsVtbl struct {
+	_IUnknownVtbl
+	GetRequest  ComProc
+	GetResponse ComProc
+}
+
+type ICoreWebView2WebResourceResponseReceivedEventArgs struct {
+	vtbl *_ICoreWebView2WebResourceResponseReceivedEventArgsVtbl
+}
+
+// GetRequest returns the request that was sent, including headers added by the network stack.
+func (i *ICoreWebView2WebResourceResponseReceivedEventArgs) GetRequest() (*ICoreWebView2WebResourceRequest, error) {
+	var request *ICoreWebView2WebResourceRequest
+	_, _, err := i.vtbl.GetRequest.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&request)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return nil, err
+	}
+	return request, nil
+}
+
+// GetResponse returns a read-only view of the response received from the server.
+func (i *ICoreWebView2WebResourceResponseReceivedEventArgs) GetResponse() (*ICoreWebView2WebResourceResponseView, error) {
+	var response *ICoreWebView2WebResourceResponseView
+	_, _, err := i.vtbl.GetResponse.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&response)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return nil, err
+	}
+	return response, nil
+}
@@ -0,0 +1,194 @@
+package edge
+
+import (
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type _ICoreWebView2WebResourceResponseViewVtbl struct {
+	_IUnknownVtbl
+	GetHeaders      ComProc
+	GetStatusCode   ComProc
+	GetReasonPhrase ComProc
+	GetContent      ComProc
+}
+
+// ICoreWebView2WebResourceResponseView is a read-only view of a response received by the browser.
+type ICoreWebView2WebResourceResponseView struct {
+	vtbl *_ICoreWebView2WebResourceResponseViewVtbl
+}
+
+func (i *ICoreWebView2WebResourceResponseView) Release() uintptr {
+	r, _, _ := i.vtbl.Release.Call(uintptr(unsafe.Pointer(i)))
+	return r
+}
+
+func (i *ICoreWebView2WebResourceResponseView) GetHeaders() (*ICoreWebView2HttpResponseHeaders, error) {
+	var headers *ICoreWebView2HttpResponseHeaders
+	_, _, err := i.vtbl.GetHeaders.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&headers)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return nil, err
+	}
+	return headers, nil
+}
+
+func (i *ICoreWebView2WebResourceResponseView) GetStatusCode() (int, error) {
+	var status int32
+	_, _, err := i.vtbl.GetStatusCode.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&status)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return 0, err
+	}
+	return int(status), nil
+}
+
+// responseContentHandlers keeps the pending GetContent handlers alive until they are invoked.
+var (
+	responseContentMu       sync.Mutex
+	responseContentHandlers = map[*ICoreWebView2WebResourceResponseViewGetContentCompletedHandler]struct{}{}
+)
+
+// responseContentHandler receives the body requested by GetContent.
+type responseContentHandler struct {
+	handler *ICoreWebView2WebResourceResponseViewGetContentCompletedHandler
+	done    func(content *IStream, err error)
+}
+
+func (h *responseContentHandler) QueryInterface(_, _ uintptr) uintptr {
+	return 0
+}
+
+func (h *responseContentHandler) AddRef() uintptr {
+	return 1
+}
+
+func (h *responseContentHandler) Release() uintptr {
+	return 1
+}
+
+func (h *responseContentHandler) GetContentCompleted(errorCode uintptr, content *IStream) uintptr {
+	responseContentMu.Lock()
+	delete(responseContentHandlers, h.handler)
+	responseContentMu.Unlock()
+	if int32(errorCode) < 0 {
+		h.done(nil, windows.Errno(errorCode))
+		return 0
+	}
+	h.done(content, nil)
+	return 0
+}
+
+// GetContent asynchronously reads the response body, done is called on the UI thread.
+// The content is nil when the response has no body, and it is only valid inside done.
+func (i *ICoreWebView2WebResourceResponseView) GetContent(done func(content *IStream, err error)) error {
+	h := &responseContentHandler{done: done}
+	h.handler = newICoreWebView2WebResourceResponseViewGetContentCompletedHandler(h)
+	responseContentMu.Lock()
+	responseContentHandlers[h.handler] = struct{}{}
+	responseContentMu.Unlock()
+	_, _, err := i.vtbl.GetContent.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(h.handler)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		responseContentMu.Lock()
+		delete(responseContentHandlers, h.handler)
+		responseContentMu.Unlock()
+		return err
+	}
+	return nil
+}
+
+type _ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerVtbl struct {
+	_IUnknownVtbl
+	Invoke ComProc
+}
+
+type ICoreWebView2WebResourceResponseViewGetContentCompletedHandler struct {
+	vtbl *_ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerVtbl
+	impl _ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerImpl
+}
+
+func _ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerIUnknownQueryInterface(this *ICoreWebView2WebResourceResponseViewGetContentCompletedHandler, refiid, object uintptr) uintptr {
+	return this.impl.QueryInterface(refiid, object)
+}
+
+func _ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerIUnknownAddRef(this *ICoreWebView2WebResourceResponseViewGetContentCompletedHandler) uintptr {
+	return this.impl.AddRef()
+}
+
+func _ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerIUnknownRelease(this *ICoreWebView2WebResourceResponseViewGetContentCompletedHandler) uintptr {
+	return this.impl.Release()
+}
+
+func _ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerInvoke(this *ICoreWebView2WebResourceResponseViewGetContentCompletedHandler, errorCode uintptr, content *IStream) uintptr {
+	return this.impl.GetContentCompleted(errorCode, content)
+}
+
+type _ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerImpl interface {
+	_IUnknownImpl
+	GetContentCompleted(errorCode uintptr, content *IStream) uintptr
+}
+
+var _ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerFn = _ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerVtbl{
+	_IUnknownVtbl{
+		NewComProc(_ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerIUnknownQueryInterface),
+		NewComProc(_ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerIUnknownAddRef),
+		NewComProc(_ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerIUnknownRelease),
+	},
+	NewComProc(_ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerInvoke),
+}
+
+func newICoreWebView2WebResourceResponseViewGetContentCompletedHandler(impl _ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerImpl) *ICoreWebView2WebResourceResponseViewGetContentCompletedHandler {
+	return &ICoreWebView2WebResourceResponseViewGetContentCompletedHandler{
+		vtbl: &_ICoreWebView2WebResourceResponseViewGetContentCompletedHandlerFn,
+		impl: impl,
+	}
+}
+
+type _ICoreWebView2HttpResponseHeadersVtbl struct {
+	_IUnknownVtbl
+	AppendHeader ComProc
+	Contains     ComProc
+	GetHeader    ComProc
+	GetHeaders   ComProc
+	GetIterator  ComProc
+}
+
+type ICoreWebView2HttpResponseHeaders struct {
+	vtbl *_ICoreWebView2HttpResponseHeadersVtbl
+}
+
+func (i *ICoreWebView2HttpResponseHeaders) Release() uintptr {
+	r, _, _ := i.vtbl.Release.Call(uintptr(unsafe.Pointer(i)))
+	return r
+}
+
+func (i *ICoreWebView2HttpResponseHeaders) GetIterator() (*ICoreWebView2HttpHeadersCollectionIterator, error) {
+	var iterator *ICoreWebView2HttpHeadersCollectionIterator
+	_, _, err := i.vtbl.GetIterator.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&iterator)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return nil, err
+	}
+	return iterator, nil
+}
+
+// All returns all headers as name/value pairs, in the order of the iterator.
+func (i *ICoreWebView2HttpResponseHeaders) All() ([][2]string, error) {
+	it, err := i.GetIterator()
+	if err != nil {
+		return nil, err
+	}
+	defer it.Release()
+	return it.all()
+}
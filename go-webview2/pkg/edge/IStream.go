@@ -0,0 +1,81 @@
+package edge
+
+import (
+	"io"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type _IStreamVtbl struct {
+	_IUnknownVtbl
+	Read         ComProc
+	Write        ComProc
+	Seek         ComProc
+	SetSize      ComProc
+	CopyTo       ComProc
+	Commit       ComProc
+	Revert       ComProc
+	LockRegion   ComProc
+	UnlockRegion ComProc
+	Stat         ComProc
+	Clone        ComProc
+}
+
+// IStream is a COM stream, used for request and response bodies.
+type IStream struct {
+	vtbl *_IStreamVtbl
+}
+
+func (i *IStream) Release() uintptr {
+	r, _, _ := i.vtbl.Release.Call(uintptr(unsafe.Pointer(i)))
+	return r
+}
+
+// Read implements io.Reader.
+func (i *IStream) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	var n uint32
+	hr, _, _ := i.vtbl.Read.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&p[0])),
+		uintptr(len(p)),
+		uintptr(unsafe.Pointer(&n)),
+	)
+	if int32(hr) < 0 {
+		return int(n), windows.Errno(hr)
+	}
+	if n == 0 {
+		return 0, io.EOF
+	}
+	return int(n), nil
+}
+
+// Seek implements io.Seeker, whence values match STREAM_SEEK_SET/CUR/END.
+func (i *IStream) Seek(offset int64, whence int) (int64, error) {
+	var pos uint64
+	var hr uintptr
+	if unsafe.Sizeof(uintptr(0)) == 4 {
+		// LARGE_INTEGER is passed by value as two 32-bit words
+		hr, _, _ = i.vtbl.Seek.Call(
+			uintptr(unsafe.Pointer(i)),
+			uintptr(uint32(offset)),
+			uintptr(uint32(offset>>32)),
+			uintptr(whence),
+			uintptr(unsafe.Pointer(&pos)),
+		)
+	} else {
+		hr, _, _ = i.vtbl.Seek.Call(
+			uintptr(unsafe.Pointer(i)),
+			uintptr(offset),
+			uintptr(whence),
+			uintptr(unsafe.Pointer(&pos)),
+		)
+	}
+	if int32(hr) < 0 {
+		return 0, windows.Errno(hr)
+	}
+	return int64(pos), nil
+}
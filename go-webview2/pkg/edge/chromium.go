@@ -28,9 +28,20 @@ type Chromium struct {
 	webResourceRequested  *iCoreWebView2WebResourceRequestedEventHandler
 	acceleratorKeyPressed *ICoreWebView2AcceleratorKeyPressedEventHandler
 	navigationCompleted   *ICoreWebView2NavigationCompletedEventHandler
+	navigationStarting    *ICoreWebView2NavigationStartingEventHandler
+	sourceChanged         *ICoreWebView2SourceChangedEventHandler
+	domContentLoaded      *ICoreWebView2DOMContentLoadedEventHandler
+	documentTitleChanged  *ICoreWebView2DocumentTitleChangedEventHandler
+	newWindowRequested    *ICoreWebView2NewWindowRequestedEventHandler
+	responseReceived      *ICoreWebView2WebResourceResponseReceivedEventHandler
+	// scriptAdded 是还在等待 AddScriptToExecuteOnDocumentCreated 完成的回调，需要保持引用
+	scriptAdded map[*ICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandler]struct{}
 
 	environment *ICoreWebView2Environment
 
+	// inset 是 WebView 到父窗口客户区边缘的距离，单位为物理像素，空出来的区域由父窗口处理鼠标
+	inset int
+
 	// Settings
 	DataPath string
 
@@ -39,11 +50,21 @@ type Chromium struct {
 	globalPermission *CoreWebView2PermissionState
 
 	// Callbacks
-	MessageCallback              func(string)
+	MessageCallback func(string)
+	// MessageSourceCallback 和 MessageCallback 一样，同时传入发送消息的页面地址，设置后不再调用 MessageCallback
+	MessageSourceCallback        func(message, source string)
 	WebResourceRequestedCallback func(request *ICoreWebView2WebResourceRequest, args *ICoreWebView2WebResourceRequestedEventArgs)
 	NavigationCompletedCallback  []func(sender *ICoreWebView2, args *ICoreWebView2NavigationCompletedEventArgs)
+	NavigationStartingCallback   []func(sender *ICoreWebView2, args *ICoreWebView2NavigationStartingEventArgs)
+	SourceChangedCallback        []func(sender *ICoreWebView2, args *ICoreWebView2SourceChangedEventArgs)
+	DOMContentLoadedCallback     []func(sender *ICoreWebView2, args *ICoreWebView2DOMContentLoadedEventArgs)
+	DocumentTitleChangedCallback []func(sender *ICoreWebView2)
+	NewWindowRequestedCallback   []func(sender *ICoreWebView2, args *ICoreWebView2NewWindowRequestedEventArgs)
 	AcceleratorKeyCallback       func(uint) bool
 
+	// WebResourceResponseReceivedCallback 在浏览器收到网络响应后调用，需要 ICoreWebView2_2
+	WebResourceResponseReceivedCallback []func(sender *ICoreWebView2, args *ICoreWebView2WebResourceResponseReceivedEventArgs)
+
 	wv2Installed bool
 }
 
@@ -67,7 +88,14 @@ func NewChromium() *Chromium {
 	e.webResourceRequested = newICoreWebView2WebResourceRequestedEventHandler(e)
 	e.acceleratorKeyPressed = newICoreWebView2AcceleratorKeyPressedEventHandler(e)
 	e.navigationCompleted = newICoreWebView2NavigationCompletedEventHandler(e)
+	e.navigationStarting = newICoreWebView2NavigationStartingEventHandler(e)
+	e.sourceChanged = newICoreWebView2SourceChangedEventHandler(e)
+	e.domContentLoaded = newICoreWebView2DOMContentLoadedEventHandler(e)
+	e.documentTitleChanged = newICoreWebView2DocumentTitleChangedEventHandler(e)
+	e.newWindowRequested = newICoreWebView2NewWindowRequestedEventHandler(e)
+	e.responseReceived = newICoreWebView2WebResourceResponseReceivedEventHandler(e)
 	e.permissions = make(map[CoreWebView2PermissionKind]CoreWebView2PermissionState)
+	e.scriptAdded = make(map[*ICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandler]struct{})
 
 	return e
 }
@@ -87,6 +115,24 @@ func (e *Chromium) CheckOrInstallWv2() bool {
 	return true
 }
 
+// SetInset 设置 WebView 到父窗口客户区边缘的距离，单位为物理像素，下次 Resize 时生效
+func (e *Chromium) SetInset(inset int) {
+	e.inset = inset
+}
+
+// bounds 返回 WebView 在父窗口中的位置，即客户区减去 inset
+func (e *Chromium) bounds() w32.Rect {
+	var bounds w32.Rect
+	_, _, _ = w32.User32GetClientRect.Call(e.hwnd, uintptr(unsafe.Pointer(&bounds)))
+	if e.inset > 0 && bounds.Right-bounds.Left > int32(2*e.inset) && bounds.Bottom-bounds.Top > int32(2*e.inset) {
+		bounds.Left += int32(e.inset)
+		bounds.Top += int32(e.inset)
+		bounds.Right -= int32(e.inset)
+		bounds.Bottom -= int32(e.inset)
+	}
+	return bounds
+}
+
 func (e *Chromium) Embed(hwnd uintptr) bool {
 	e.hwnd = hwnd
 
@@ -154,6 +200,54 @@ func (e *Chromium) Init(script string) {
 	)
 }
 
+// scriptAddedHandler 接收 AddScript 注入完成后的脚本 id
+type scriptAddedHandler struct {
+	e       *Chromium
+	handler *ICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandler
+	done    func(id string)
+}
+
+func (h *scriptAddedHandler) QueryInterface(_, _ uintptr) uintptr {
+	return 0
+}
+
+func (h *scriptAddedHandler) AddRef() uintptr {
+	return 1
+}
+
+func (h *scriptAddedHandler) Release() uintptr {
+	return 1
+}
+
+func (h *scriptAddedHandler) AddScriptToExecuteOnDocumentCreatedCompleted(errorCode uintptr, id *uint16) uintptr {
+	delete(h.e.scriptAdded, h.handler)
+	if errorCode == 0 && h.done != nil {
+		h.done(w32.Utf16PtrToString(id))
+	}
+	return 0
+}
+
+// AddScript 和 Init 一样注入在每个页面创建时执行的脚本，
+// 注入完成后通过 done 返回脚本 id，可以用于 RemoveScript
+func (e *Chromium) AddScript(script string, done func(id string)) {
+	h := &scriptAddedHandler{e: e, done: done}
+	h.handler = newICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandler(h)
+	e.scriptAdded[h.handler] = struct{}{}
+	_, _, _ = e.webview.vtbl.AddScriptToExecuteOnDocumentCreated.Call(
+		uintptr(unsafe.Pointer(e.webview)),
+		uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(script))),
+		uintptr(unsafe.Pointer(h.handler)),
+	)
+}
+
+// RemoveScript 移除通过 AddScript 注入的脚本，对已经打开的页面没有影响
+func (e *Chromium) RemoveScript(id string) {
+	_, _, _ = e.webview.vtbl.RemoveScriptToExecuteOnDocumentCreated.Call(
+		uintptr(unsafe.Pointer(e.webview)),
+		uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(id))),
+	)
+}
+
 func (e *Chromium) Eval(script string) {
 	_script, err := windows.UTF16PtrFromString(script)
 	if err != nil {
@@ -240,6 +334,32 @@ func (e *Chromium) CreateCoreWebView2ControllerCompleted(res uintptr, controller
 		uintptr(unsafe.Pointer(e.navigationCompleted)),
 		uintptr(unsafe.Pointer(&token)),
 	)
+	_, _, _ = e.webview.vtbl.AddNavigationStarting.Call(
+		uintptr(unsafe.Pointer(e.webview)),
+		uintptr(unsafe.Pointer(e.navigationStarting)),
+		uintptr(unsafe.Pointer(&token)),
+	)
+	_, _, _ = e.webview.vtbl.AddSourceChanged.Call(
+		uintptr(unsafe.Pointer(e.webview)),
+		uintptr(unsafe.Pointer(e.sourceChanged)),
+		uintptr(unsafe.Pointer(&token)),
+	)
+	_, _, _ = e.webview.vtbl.AddDocumentTitleChanged.Call(
+		uintptr(unsafe.Pointer(e.webview)),
+		uintptr(unsafe.Pointer(e.documentTitleChanged)),
+		uintptr(unsafe.Pointer(&token)),
+	)
+	_, _, _ = e.webview.vtbl.AddNewWindowRequested.Call(
+		uintptr(unsafe.Pointer(e.webview)),
+		uintptr(unsafe.Pointer(e.newWindowRequested)),
+		uintptr(unsafe.Pointer(&token)),
+	)
+	// DOMContentLoaded 和 WebResourceResponseReceived 需要 ICoreWebView2_2，旧版本的 WebView2 Runtime 不支持
+	if wv2 := e.webview.GetICoreWebView2_2(); wv2 != nil {
+		_ = wv2.AddDomContentLoaded(e.domContentLoaded, &token)
+		_ = wv2.AddWebResourceResponseReceived(e.responseReceived, &token)
+		wv2.Release()
+	}
 
 	_ = e.controller.AddAcceleratorKeyPressed(e.acceleratorKeyPressed, &token)
 
@@ -258,7 +378,15 @@ func (e *Chromium) MessageReceived(sender *ICoreWebView2, args *iCoreWebView2Web
 		uintptr(unsafe.Pointer(args)),
 		uintptr(unsafe.Pointer(&message)),
 	)
-	if e.MessageCallback != nil {
+	if e.MessageSourceCallback != nil {
+		var source *uint16
+		_, _, _ = args.vtbl.GetSource.Call(
+			uintptr(unsafe.Pointer(args)),
+			uintptr(unsafe.Pointer(&source)),
+		)
+		e.MessageSourceCallback(w32.Utf16PtrToString(message), w32.Utf16PtrToString(source))
+		windows.CoTaskMemFree(unsafe.Pointer(source))
+	} else if e.MessageCallback != nil {
 		e.MessageCallback(w32.Utf16PtrToString(message))
 	}
 	_, _, _ = sender.vtbl.PostWebMessageAsString.Call(
@@ -393,3 +521,69 @@ func (e *Chromium) PutIsBuiltInErrorPageEnabled(enable bool) error {
 func (e *Chromium) OnNavigationCompleted(h func(sender *ICoreWebView2, args *ICoreWebView2NavigationCompletedEventArgs)) {
 	e.NavigationCompletedCallback = append(e.NavigationCompletedCallback, h)
 }
+
+func (e *Chromium) NavigationStarting(sender *ICoreWebView2, args *ICoreWebView2NavigationStartingEventArgs) uintptr {
+	for _, h := range e.NavigationStartingCallback {
+		h(sender, args)
+	}
+	return 0
+}
+
+func (e *Chromium) OnNavigationStarting(h func(sender *ICoreWebView2, args *ICoreWebView2NavigationStartingEventArgs)) {
+	e.NavigationStartingCallback = append(e.NavigationStartingCallback, h)
+}
+
+func (e *Chromium) SourceChanged(sender *ICoreWebView2, args *ICoreWebView2SourceChangedEventArgs) uintptr {
+	for _, h := range e.SourceChangedCallback {
+		h(sender, args)
+	}
+	return 0
+}
+
+func (e *Chromium) OnSourceChanged(h func(sender *ICoreWebView2, args *ICoreWebView2SourceChangedEventArgs)) {
+	e.SourceChangedCallback = append(e.SourceChangedCallback, h)
+}
+
+func (e *Chromium) DOMContentLoaded(sender *ICoreWebView2, args *ICoreWebView2DOMContentLoadedEventArgs) uintptr {
+	for _, h := range e.DOMContentLoadedCallback {
+		h(sender, args)
+	}
+	return 0
+}
+
+func (e *Chromium) OnDOMContentLoaded(h func(sender *ICoreWebView2, args *ICoreWebView2DOMContentLoadedEventArgs)) {
+	e.DOMContentLoadedCallback = append(e.DOMContentLoadedCallback, h)
+}
+
+func (e *Chromium) DocumentTitleChanged(sender *ICoreWebView2, _ *IUnknown) uintptr {
+	for _, h := range e.DocumentTitleChangedCallback {
+		h(sender)
+	}
+	return 0
+}
+
+func (e *Chromium) OnDocumentTitleChanged(h func(sender *ICoreWebView2)) {
+	e.DocumentTitleChangedCallback = append(e.DocumentTitleChangedCallback, h)
+}
+
+func (e *Chromium) NewWindowRequested(sender *ICoreWebView2, args *ICoreWebView2NewWindowRequestedEventArgs) uintptr {
+	for _, h := range e.NewWindowRequestedCallback {
+		h(sender, args)
+	}
+	return 0
+}
+
+func (e *Chromium) OnNewWindowRequested(h func(sender *ICoreWebView2, args *ICoreWebView2NewWindowRequestedEventArgs)) {
+	e.NewWindowRequestedCallback = append(e.NewWindowRequestedCallback, h)
+}
+
+func (e *Chromium) WebResourceResponseReceived(sender *ICoreWebView2, args *ICoreWebView2WebResourceResponseReceivedEventArgs) uintptr {
+	for _, h := range e.WebResourceResponseReceivedCallback {
+		h(sender, args)
+	}
+	return 0
+}
+
+func (e *Chromium) OnWebResourceResponseReceived(h func(sender *ICoreWebView2, args *ICoreWebView2WebResourceResponseReceivedEventArgs)) {
+	e.WebResourceResponseReceivedCallback = append(e.WebResourceResponseReceivedCallback, h)
+}
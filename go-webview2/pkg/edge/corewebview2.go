@@ -74,6 +74,12 @@ func (i *_IUnknownVtbl) CallRelease(this unsafe.Pointer) error {
 	return nil
 }
 
+// IUnknown is used for event args that carry no data, such as the args of
+// DocumentTitleChanged.
+type IUnknown struct {
+	vtbl *_IUnknownVtbl
+}
+
 type _IUnknownImpl interface {
 	QueryInterface(refiid, object uintptr) uintptr
 	AddRef() uintptr
@@ -419,3 +425,31 @@ func (i *ICoreWebView2) AddNavigationCompleted(eventHandler *ICoreWebView2Naviga
 	}
 	return nil
 }
+
+func (i *ICoreWebView2) GetSource() (string, error) {
+	var _uri *uint16
+	_, _, err := i.vtbl.GetSource.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&_uri)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return "", err
+	}
+	uri := windows.UTF16PtrToString(_uri)
+	windows.CoTaskMemFree(unsafe.Pointer(_uri))
+	return uri, nil
+}
+
+func (i *ICoreWebView2) GetDocumentTitle() (string, error) {
+	var _title *uint16
+	_, _, err := i.vtbl.GetDocumentTitle.Call(
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(&_title)),
+	)
+	if err != windows.ERROR_SUCCESS {
+		return "", err
+	}
+	title := windows.UTF16PtrToString(_title)
+	windows.CoTaskMemFree(unsafe.Pointer(_title))
+	return title, nil
+}
@@ -0,0 +1,145 @@
+package webview2
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// NewWindowAction 是页面通过 window.open 或者 target=_blank 打开允许的地址时的处理方式，
+// 不允许的地址总是在系统默认浏览器中打开
+type NewWindowAction int
+
+const (
+	// NewWindowCurrent 在当前窗口中打开
+	NewWindowCurrent NewWindowAction = iota
+	// NewWindowDesktop 使用当前窗口的配置打开一个新的桌面窗口
+	NewWindowDesktop
+	// NewWindowExternal 在系统默认浏览器中打开
+	NewWindowExternal
+)
+
+// NavigationPolicy 限制页面可以跳转的地址，不允许的跳转会被取消，并在系统默认浏览器中打开。
+// 规则中 * 匹配任意多个字符，? 匹配一个字符，如 https://*.example.com/*，
+// 带 :// 的规则分别匹配协议、host 和路径，协议和 host 中的通配符不会匹配到路径，
+// 没有路径时匹配该 host 下的所有地址。AppOrigin、about: 和 data: 的地址总是允许
+type NavigationPolicy struct {
+	// Allow 是允许在窗口中打开的地址，为空时允许 Deny 以外的所有地址
+	Allow []string
+	// Deny 是不允许在窗口中打开的地址，优先于 Allow
+	Deny []string
+	// NewWindow 是打开新窗口时的处理方式
+	NewWindow NewWindowAction
+	// ExternalSchemes 是可以在系统中打开的协议，为空时为 http、https 和 mailto，
+	// 其他协议的地址只会取消跳转，避免页面通过自定义协议启动本地程序
+	ExternalSchemes []string
+}
+
+// Allowed 返回 uri 是否可以在窗口中打开
+func (p *NavigationPolicy) Allowed(uri string) bool {
+	if matchNavigation(AppOrigin+"/*", uri) || strings.HasPrefix(uri, "about:") || strings.HasPrefix(uri, "data:") {
+		return true
+	}
+	for _, pattern := range p.Deny {
+		if matchNavigation(pattern, uri) {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range p.Allow {
+		if matchNavigation(pattern, uri) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchNavigation 判断 uri 是否匹配 NavigationPolicy 的规则 pattern，
+// 不带 :// 的规则如 mailto:* 按整个地址匹配
+func matchNavigation(pattern, uri string) bool {
+	i := strings.Index(pattern, "://")
+	if i < 0 {
+		return matchURIPattern(pattern, uri)
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	scheme, host, rest := pattern[:i], pattern[i+3:], ""
+	if j := strings.IndexByte(host, '/'); j >= 0 {
+		host, rest = host[:j], host[j:]
+	}
+	// path.Match 的 * 不匹配 /，host 中的通配符只能匹配 host
+	if ok, _ := path.Match(strings.ToLower(scheme), strings.ToLower(u.Scheme)); !ok {
+		return false
+	}
+	if ok, _ := path.Match(strings.ToLower(host), strings.ToLower(u.Host)); !ok {
+		return false
+	}
+	return rest == "" || matchURIPattern(rest, u.RequestURI())
+}
+
+// external 返回 uri 是否可以在系统默认浏览器中打开
+func (p *NavigationPolicy) external(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	schemes := p.ExternalSchemes
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https", "mailto"}
+	}
+	for _, s := range schemes {
+		if strings.EqualFold(s, u.Scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// newWindowAction 返回打开新窗口 uri 时的处理方式
+func (p *NavigationPolicy) newWindowAction(uri string) NewWindowAction {
+	if !p.Allowed(uri) {
+		return NewWindowExternal
+	}
+	return p.NewWindow
+}
+
+// popupOptions 返回在新的桌面窗口中打开 uri 的配置，只继承和页面内容相关的配置，
+// Parent、Modal、PersistState、AlwaysOnTop、Cache 等属于打开者窗口自身的配置不会继承
+func popupOptions(opener WebViewOptions, uri string) WebViewOptions {
+	return WebViewOptions{
+		StartURL:         uri,
+		FallbackPage:     opener.FallbackPage,
+		Debug:            opener.Debug,
+		DataPath:         opener.DataPath,
+		AutoFocus:        opener.AutoFocus,
+		Logger:           opener.Logger,
+		Assets:           opener.Assets,
+		Handler:          opener.Handler,
+		VirtualHosts:     opener.VirtualHosts,
+		AllowedOrigins:   opener.AllowedOrigins,
+		NavigationPolicy: opener.NavigationPolicy,
+		Fallback:         opener.Fallback,
+		WindowOptions: WindowOptions{
+			Title:  opener.WindowOptions.Title,
+			Width:  opener.WindowOptions.Width,
+			Height: opener.WindowOptions.Height,
+			IconId: opener.WindowOptions.IconId,
+			Icon:   opener.WindowOptions.Icon,
+		},
+	}
+}
+
+// enforce 通过 OnNavigationStarting 取消不允许的跳转，open 在系统默认浏览器中打开地址
+func (p *NavigationPolicy) enforce(n *navigationEvents, open func(uri string)) {
+	n.OnNavigationStarting(func(e *NavigationStartingEvent) {
+		if p.Allowed(e.URL) {
+			return
+		}
+		e.Cancel()
+		open(e.URL)
+	})
+}
@@ -0,0 +1,125 @@
+package webview2
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestNavigationPolicyAllowed(t *testing.T) {
+	p := &NavigationPolicy{
+		Allow: []string{"https://*.example.com/*", "https://docs.local/guide/*", "http://localhost:5173", "mailto:*"},
+		Deny:  []string{"https://admin.example.com/*", "https://*.example.com/private/*"},
+	}
+	tests := []struct {
+		uri  string
+		want bool
+	}{
+		{"https://www.example.com/", true},
+		{"https://a.b.example.com/x?y=z", true},
+		{"https://WWW.Example.com/", true},
+		{"https://example.com/", false},
+		// host 中的 * 不能匹配到路径或者其他 host
+		{"https://evil.com/x.example.com/", false},
+		{"https://evil.com/?x.example.com/", false},
+		{"https://www.example.com.evil.com/", false},
+		{"https://www.example.com@evil.com/", false},
+		{"http://www.example.com/", false},
+		{"https://docs.local/guide/intro", true},
+		{"https://docs.local/other", false},
+		{"http://localhost:5173/any/path", true},
+		{"http://localhost:8080/", false},
+		{"mailto:someone@example.com", true},
+		{"ftp://www.example.com/", false},
+		// Deny 优先于 Allow
+		{"https://admin.example.com/users", false},
+		{"https://www.example.com/private/a", false},
+		// AppOrigin、about: 和 data: 总是允许
+		{AppOrigin + "/index.html", true},
+		{"about:blank", true},
+		{"data:text/html,hi", true},
+	}
+	for _, tt := range tests {
+		if got := p.Allowed(tt.uri); got != tt.want {
+			t.Errorf("Allowed(%q) = %v, want %v", tt.uri, got, tt.want)
+		}
+	}
+}
+
+func TestNavigationPolicyDenyOnly(t *testing.T) {
+	p := &NavigationPolicy{Deny: []string{"https://*.ads.com"}}
+	for uri, want := range map[string]bool{
+		"https://example.com/":        true,
+		"https://x.ads.com/banner":    false,
+		"https://example.com/ads.com": true,
+		"about:blank":                 true,
+	} {
+		if got := p.Allowed(uri); got != want {
+			t.Errorf("Allowed(%q) = %v, want %v", uri, got, want)
+		}
+	}
+
+	// Deny 不能禁止内部页面
+	p = &NavigationPolicy{Deny: []string{"*"}}
+	for _, uri := range []string{AppOrigin + "/", "about:blank", "data:text/html,x"} {
+		if !p.Allowed(uri) {
+			t.Errorf("Allowed(%q) = false with Deny *", uri)
+		}
+	}
+	if p.Allowed("https://example.com/") {
+		t.Error("Allowed(https://example.com/) = true with Deny *")
+	}
+}
+
+func TestNavigationPolicyNewWindow(t *testing.T) {
+	p := &NavigationPolicy{Allow: []string{"https://app.example.com/*"}, NewWindow: NewWindowCurrent}
+	if got := p.newWindowAction("https://app.example.com/a"); got != NewWindowCurrent {
+		t.Errorf("newWindowAction(allowed) = %v, want %v", got, NewWindowCurrent)
+	}
+	if got := p.newWindowAction("https://other.com/"); got != NewWindowExternal {
+		t.Errorf("newWindowAction(denied) = %v, want %v", got, NewWindowExternal)
+	}
+
+	for uri, want := range map[string]bool{
+		"https://other.com/":   true,
+		"mailto:a@example.com": true,
+		"ms-settings:privacy":  false,
+		"file:///C:/Windows":   false,
+	} {
+		if got := p.external(uri); got != want {
+			t.Errorf("external(%q) = %v, want %v", uri, got, want)
+		}
+	}
+}
+
+func TestPopupOptions(t *testing.T) {
+	policy := &NavigationPolicy{NewWindow: NewWindowDesktop}
+	handler := http.NotFoundHandler()
+	opener := WebViewOptions{
+		StartURL:         "https://app.example.com/",
+		Debug:            true,
+		DataPath:         "data",
+		Handler:          handler,
+		AllowedOrigins:   []string{"https://app.example.com"},
+		NavigationPolicy: policy,
+		PersistState:     true,
+		Cache:            &CacheOptions{},
+		Modal:            true,
+		WindowOptions:    WindowOptions{Title: "app", Width: 800, Height: 600, Center: true, Frameless: true},
+	}
+	got := popupOptions(opener, "https://app.example.com/popup")
+	if got.StartURL != "https://app.example.com/popup" {
+		t.Errorf("StartURL = %q", got.StartURL)
+	}
+	if !got.Debug || got.DataPath != "data" || got.NavigationPolicy != policy ||
+		!reflect.DeepEqual(got.AllowedOrigins, opener.AllowedOrigins) || got.Handler == nil {
+		t.Errorf("page options not inherited: %+v", got)
+	}
+	if got.PersistState || got.Cache != nil || got.Modal || got.Parent != nil {
+		t.Errorf("window options inherited: %+v", got)
+	}
+	want := WindowOptions{Title: "app", Width: 800, Height: 600}
+	if got.WindowOptions != want {
+		t.Errorf("WindowOptions = %+v, want %+v", got.WindowOptions, want)
+	}
+}
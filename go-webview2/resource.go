@@ -0,0 +1,287 @@
+package webview2
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// AppOrigin 是 WebViewOptions.Assets 和 WebViewOptions.Handler 提供页面使用的地址，
+// 页面中发往该地址的请求都会交给 Go 处理，不会经过网络
+const AppOrigin = "https://app.local"
+
+// rawRequest 是拦截到的页面请求，由各平台从浏览器的请求对象中读取
+type rawRequest struct {
+	Method string
+	URI    string
+	// Header 是请求头，按浏览器给出的顺序排列
+	Header [][2]string
+	// Body 是请求体，没有请求体时为 nil
+	Body []byte
+}
+
+// httpRequest 转换为 *http.Request，和 net/http 服务端收到的请求一致
+func (r *rawRequest) httpRequest(ctx context.Context) (*http.Request, error) {
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	var body io.Reader
+	if r.Body != nil {
+		body = bytes.NewReader(r.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, r.URI, body)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range r.Header {
+		req.Header.Add(h[0], h[1])
+	}
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+		req.Header.Del("Host")
+	}
+	req.RequestURI = req.URL.RequestURI()
+	req.RemoteAddr = "webview"
+	return req, nil
+}
+
+// newRawRequest 从 *http.Request 读取请求，用于 Headless 模拟页面请求
+func newRawRequest(req *http.Request) (*rawRequest, error) {
+	r := &rawRequest{Method: req.Method, URI: req.URL.String()}
+	keys := make([]string, 0, len(req.Header))
+	for k := range req.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range req.Header[k] {
+			r.Header = append(r.Header, [2]string{k, v})
+		}
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		r.Body = b
+	}
+	return r, nil
+}
+
+// resourceResponse 是 http.Handler 处理拦截到的请求后的结果，会转换为 WebView2 的响应
+type resourceResponse struct {
+	status    int
+	header    http.Header
+	body      bytes.Buffer
+	wroteHead bool
+}
+
+var _ http.ResponseWriter = &resourceResponse{}
+
+func newResourceResponse() *resourceResponse {
+	return &resourceResponse{header: http.Header{}}
+}
+
+func (r *resourceResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *resourceResponse) WriteHeader(code int) {
+	if r.wroteHead {
+		return
+	}
+	r.wroteHead = true
+	r.status = code
+}
+
+func (r *resourceResponse) Write(p []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	if r.body.Len() == 0 && len(p) > 0 && r.header.Get("Content-Type") == "" {
+		// 和 net/http 一样，没有设置 Content-Type 时根据内容判断
+		r.header.Set("Content-Type", http.DetectContentType(p))
+	}
+	return r.body.Write(p)
+}
+
+// reason 返回状态码对应的描述，如 OK、Not Found
+func (r *resourceResponse) reason() string {
+	if text := http.StatusText(r.status); text != "" {
+		return text
+	}
+	return "Status " + strconv.Itoa(r.status)
+}
+
+// headerString 返回 CreateWebResourceResponse 需要的响应头格式，每行一个 Name: value
+func (r *resourceResponse) headerString() string {
+	keys := make([]string, 0, len(r.header))
+	for k := range r.header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	lines := []string{}
+	for _, k := range keys {
+		for _, v := range r.header[k] {
+			lines = append(lines, k+": "+v)
+		}
+	}
+	return strings.Join(lines, "\r\n")
+}
+
+// httpResponse 把结果转换为 *http.Response，用于 Headless
+func (r *resourceResponse) httpResponse(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(r.status) + " " + r.reason(),
+		StatusCode:    r.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.header,
+		Body:          io.NopCloser(bytes.NewReader(r.body.Bytes())),
+		ContentLength: int64(r.body.Len()),
+		Request:       req,
+	}
+}
+
+// ServeHTTP 把响应写入 w，用于 Respond
+func (r *resourceResponse) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	for k, v := range r.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(r.status)
+	_, _ = io.Copy(w, bytes.NewReader(r.body.Bytes()))
+}
+
+// serveResource 用 h 处理拦截到的请求，和普通的 net/http 服务一样
+func serveResource(h http.Handler, req *http.Request) *resourceResponse {
+	res := newResourceResponse()
+	h.ServeHTTP(res, req)
+	if !res.wroteHead {
+		res.WriteHeader(http.StatusOK)
+	}
+	if req.Method == http.MethodHead {
+		res.body.Reset()
+	}
+	return res
+}
+
+// resourceRoute 是通过 Handle 注册的请求处理
+type resourceRoute struct {
+	pattern string
+	handler http.Handler
+}
+
+// resourceRouter 按地址把拦截到的请求交给对应的 http.Handler
+type resourceRouter struct {
+	m           sync.Mutex
+	routes      []*resourceRoute
+	middlewares []ResourceMiddleware
+	// global 是通过 use 注册的拦截所有请求的中间件数量，
+	// patterns 是通过 useFor 注册的中间件需要拦截的地址
+	global   int
+	patterns []string
+}
+
+// handle 注册 pattern 的处理，pattern 已经注册过时替换原来的处理并返回 false
+func (r *resourceRouter) handle(pattern string, h http.Handler) bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+	for _, route := range r.routes {
+		if route.pattern == pattern {
+			route.handler = h
+			return false
+		}
+	}
+	r.routes = append(r.routes, &resourceRoute{pattern, h})
+	return true
+}
+
+// remove 移除 pattern 的处理
+func (r *resourceRouter) remove(pattern string) bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+	for i, route := range r.routes {
+		if route.pattern == pattern {
+			r.routes = append(r.routes[:i:i], r.routes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// match 返回第一个匹配 uri 的处理，没有匹配时返回 nil
+func (r *resourceRouter) match(uri string) http.Handler {
+	r.m.Lock()
+	defer r.m.Unlock()
+	for _, route := range r.routes {
+		if matchURIPattern(route.pattern, uri) {
+			return route.handler
+		}
+	}
+	return nil
+}
+
+// matchURIPattern 和 WebView2 的 AddWebResourceRequestedFilter 规则一致，
+// * 匹配任意多个字符，? 匹配一个字符
+func matchURIPattern(pattern, uri string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(uri); i >= 0; i-- {
+				if matchURIPattern(pattern[1:], uri[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(uri) == 0 {
+				return false
+			}
+		default:
+			if len(uri) == 0 || pattern[0] != uri[0] {
+				return false
+			}
+		}
+		pattern, uri = pattern[1:], uri[1:]
+	}
+	return len(uri) == 0
+}
+
+// appHandler 返回 Assets 和 Handler 配置对应的 http.Handler，Handler 优先，都没有配置时返回 nil
+func appHandler(options WebViewOptions) http.Handler {
+	if options.Handler != nil {
+		return options.Handler
+	}
+	if options.Assets != nil {
+		return AssetsHandler(options.Assets)
+	}
+	return nil
+}
+
+// AssetsHandler 返回提供 assets 中静态文件的 http.Handler，
+// 找不到文件并且路径没有扩展名时返回 /index.html，用于单页应用的前端路由
+func AssetsHandler(assets fs.FS) http.Handler {
+	files := http.FileServer(http.FS(assets))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if name == "" {
+			name = "."
+		}
+		if _, err := fs.Stat(assets, name); err != nil && path.Ext(name) == "" {
+			if _, err := fs.Stat(assets, "index.html"); err == nil {
+				r2 := r.Clone(r.Context())
+				r2.URL.Path = "/"
+				files.ServeHTTP(w, r2)
+				return
+			}
+		}
+		files.ServeHTTP(w, r)
+	})
+}
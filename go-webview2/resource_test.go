@@ -0,0 +1,128 @@
+package webview2
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func text(s string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, s)
+	})
+}
+
+func fetch(t *testing.T, h *Headless, uri string) (int, string) {
+	t.Helper()
+	res, err := h.Fetch(httptest.NewRequest(http.MethodGet, uri, nil))
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	return res.StatusCode, string(body)
+}
+
+func TestResourceRouterOrder(t *testing.T) {
+	r := &resourceRouter{}
+	if !r.handle("https://api.example.com/v1/*", text("v1")) {
+		t.Fatal("handle returned false for a new pattern")
+	}
+	r.handle("https://api.example.com/*", text("api"))
+	r.handle("https://api.example.com/v1/users", text("users"))
+
+	serve := func(uri string) string {
+		h := r.match(uri)
+		if h == nil {
+			return ""
+		}
+		res := serveResource(h, httptest.NewRequest(http.MethodGet, uri, nil))
+		return res.body.String()
+	}
+	// 先注册的规则优先，后注册的更具体的规则不会覆盖
+	tests := map[string]string{
+		"https://api.example.com/v1/users": "v1",
+		"https://api.example.com/v2/users": "api",
+		"https://other.example.com/":       "",
+	}
+	for uri, want := range tests {
+		if got := serve(uri); got != want {
+			t.Errorf("%s served %q, want %q", uri, got, want)
+		}
+	}
+
+	// 重复注册时替换原来的处理并保持顺序
+	if r.handle("https://api.example.com/v1/*", text("v1 replaced")) {
+		t.Error("handle returned true for a registered pattern")
+	}
+	if got := serve("https://api.example.com/v1/users"); got != "v1 replaced" {
+		t.Errorf("after replace served %q", got)
+	}
+
+	if !r.remove("https://api.example.com/v1/*") {
+		t.Error("remove returned false for a registered pattern")
+	}
+	if r.remove("https://api.example.com/v1/*") {
+		t.Error("remove returned true twice")
+	}
+	if got := serve("https://api.example.com/v1/users"); got != "api" {
+		t.Errorf("after remove served %q, want api", got)
+	}
+}
+
+func TestResourceMiddlewareOrder(t *testing.T) {
+	h := NewHeadless(WebViewOptions{})
+	h.Handle(AppOrigin+"/*", text("app"))
+
+	var order []string
+	trace := func(name string) ResourceMiddleware {
+		return func(next ResourceHandler) ResourceHandler {
+			return func(r *ResourceRequest) Action {
+				order = append(order, name)
+				return next(r)
+			}
+		}
+	}
+	h.Use(trace("first"))
+	h.Use(trace("second"))
+	h.Use(Filter(AppOrigin+"/blocked/*", ResourceAll, func(r *ResourceRequest) Action {
+		return Block()
+	}))
+
+	if code, body := fetch(t, h, AppOrigin+"/index.html"); code != http.StatusOK || body != "app" {
+		t.Errorf("index = %d %q", code, body)
+	}
+	if got := strings.Join(order, ","); got != "first,second" {
+		t.Errorf("middleware order = %s, want first,second", got)
+	}
+	if code, _ := fetch(t, h, AppOrigin+"/blocked/x"); code != http.StatusForbidden {
+		t.Errorf("blocked = %d, want %d", code, http.StatusForbidden)
+	}
+	if code, body := fetch(t, h, "https://example.com/"); code != 0 {
+		t.Errorf("unhandled = %d %q, want an error", code, body)
+	}
+}
+
+func TestMatchURIPattern(t *testing.T) {
+	tests := []struct {
+		pattern, uri string
+		want         bool
+	}{
+		{"https://app.local/*", "https://app.local/", true},
+		{"https://app.local/*", "https://app.local/a/b?c=d", true},
+		{"https://app.local/*", "https://app.local", false},
+		{"https://*.example.com/*", "https://a.b.example.com/x", true},
+		{"https://app.local/?.js", "https://app.local/a.js", true},
+		{"https://app.local/?.js", "https://app.local/ab.js", false},
+		{"*", "anything", true},
+		{"", "", true},
+		{"", "x", false},
+	}
+	for _, tt := range tests {
+		if got := matchURIPattern(tt.pattern, tt.uri); got != tt.want {
+			t.Errorf("matchURIPattern(%q, %q) = %v, want %v", tt.pattern, tt.uri, got, tt.want)
+		}
+	}
+}
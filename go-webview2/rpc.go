@@ -0,0 +1,340 @@
+package webview2
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+var streamWindowJS = strconv.Itoa(streamWindow)
+
+// RPCVersion 是 Go 与 JS 之间消息协议的版本，消息格式遵循 JSON-RPC 2.0
+const RPCVersion = "2.0"
+
+// JSON-RPC 2.0 定义的错误码
+const (
+	RPCParseError     = -32700
+	RPCInvalidRequest = -32600
+	RPCMethodNotFound = -32601
+	RPCInvalidParams  = -32602
+	RPCInternalError  = -32603
+	// RPCServerError 是被调用的函数返回 error 时使用的错误码
+	RPCServerError = -32000
+	// RPCCanceled 是调用被取消或者超时使用的错误码
+	RPCCanceled = -32800
+	// RPCForbidden 是调用方页面不在绑定函数允许的地址中使用的错误码
+	RPCForbidden = -32801
+)
+
+// RPCError 是 RPC 调用失败时的错误对象。
+// 绑定的 Go 函数返回 *RPCError 时会原样传给 JS，返回其他 error 时使用 RPCServerError 错误码
+type RPCError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return "rpc error " + strconv.Itoa(e.Code) + ": " + e.Message
+}
+
+func toRPCError(err error, code int) *RPCError {
+	if e, ok := err.(*RPCError); ok {
+		return e
+	}
+	return &RPCError{Code: code, Message: err.Error()}
+}
+
+// RPCMessage 是 Go 与 JS 之间传递的消息：
+//
+//   - 请求：有 method 和 id，对方必须回复响应
+//   - 通知：有 method 没有 id，对方不回复
+//   - 响应：有 id 以及 result 或 error 其中之一
+type RPCMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// IsRequest 消息是否为需要回复的请求
+func (m *RPCMessage) IsRequest() bool { return m.Method != "" && len(m.ID) > 0 }
+
+// IsNotification 消息是否为不需要回复的通知
+func (m *RPCMessage) IsNotification() bool { return m.Method != "" && len(m.ID) == 0 }
+
+// IsResponse 消息是否为请求的响应
+func (m *RPCMessage) IsResponse() bool {
+	return m.Method == "" && len(m.ID) > 0 && (len(m.Result) > 0 || m.Error != nil)
+}
+
+// ParamList 把 params 解析为参数列表，只支持按位置传参的数组形式
+func (m *RPCMessage) ParamList() ([]json.RawMessage, error) {
+	if len(m.Params) == 0 || string(m.Params) == "null" {
+		return nil, nil
+	}
+	var params []json.RawMessage
+	if err := json.Unmarshal(m.Params, &params); err != nil {
+		return nil, &RPCError{Code: RPCInvalidParams, Message: "params must be an array"}
+	}
+	return params, nil
+}
+
+// NewRPCRequest 创建一个请求消息
+func NewRPCRequest(id interface{}, method string, params ...interface{}) (*RPCMessage, error) {
+	m, err := NewRPCNotification(method, params...)
+	if err != nil {
+		return nil, err
+	}
+	if m.ID, err = json.Marshal(id); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NewRPCNotification 创建一个通知消息
+func NewRPCNotification(method string, params ...interface{}) (*RPCMessage, error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+	p, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCMessage{JSONRPC: RPCVersion, Method: method, Params: p}, nil
+}
+
+// NewRPCResponse 创建一个响应消息，err 不为 nil 时为错误响应，否则 result 为响应结果
+func NewRPCResponse(id json.RawMessage, result interface{}, err error) *RPCMessage {
+	m := &RPCMessage{JSONRPC: RPCVersion, ID: id}
+	if err != nil {
+		m.Error = toRPCError(err, RPCServerError)
+		return m
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		m.Error = &RPCError{Code: RPCInternalError, Message: err.Error()}
+		return m
+	}
+	m.Result = b
+	return m
+}
+
+// DecodeRPCMessage 解析并校验一条消息，失败时返回 *RPCError，
+// 如果已经解析出消息 id，返回的消息不为 nil，可用于回复错误响应
+func DecodeRPCMessage(data []byte) (*RPCMessage, error) {
+	m := &RPCMessage{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, &RPCError{Code: RPCParseError, Message: err.Error()}
+	}
+	if m.JSONRPC != RPCVersion {
+		return m, &RPCError{Code: RPCInvalidRequest, Message: "unsupported jsonrpc version " + strconv.Quote(m.JSONRPC)}
+	}
+	if !m.IsRequest() && !m.IsNotification() && !m.IsResponse() {
+		return m, &RPCError{Code: RPCInvalidRequest, Message: "message is neither a request, a notification nor a response"}
+	}
+	return m, nil
+}
+
+// EncodeRPCMessage 把消息编码为 JSON
+func EncodeRPCMessage(m *RPCMessage) ([]byte, error) {
+	m.JSONRPC = RPCVersion
+	return json.Marshal(m)
+}
+
+// rpcRecvPrefix 是 Go 发给 JS 的消息在页面中执行的脚本前缀
+const rpcRecvPrefix = "window._rpc.recv("
+
+// rpcScript 生成把消息交给页面 RPC 运行时处理的脚本
+func rpcScript(data []byte) string {
+	return rpcRecvPrefix + string(data) + ")"
+}
+
+// parseRPCScript 从 rpcScript 生成的脚本中解析出消息
+func parseRPCScript(js string) (*RPCMessage, bool) {
+	if !strings.HasPrefix(js, rpcRecvPrefix) || !strings.HasSuffix(js, ")") {
+		return nil, false
+	}
+	data := strings.TrimSuffix(strings.TrimPrefix(js, rpcRecvPrefix), ")")
+	m := &RPCMessage{}
+	if err := json.Unmarshal([]byte(data), m); err != nil {
+		return nil, false
+	}
+	return m, true
+}
+
+// rpcRuntime 是页面中的 RPC 运行时，负责收发消息，每个页面只初始化一次。
+// window._rpc.call 发起请求并返回 Promise，window._rpc.notify 发送通知，
+// window._rpc.recv 接收 Go 发来的消息，window._rpc.handlers 注册可被 Go 调用的方法
+var rpcRuntime = `(function() {
+	if (window._rpc && window._rpc.version) return;
+	var RPC = window._rpc = {
+		version: "` + RPCVersion + `",
+		nextSeq: 1,
+		pending: {},
+		handlers: {
+			"$eval": function(js) {
+				return (0, eval)(js);
+			},
+			"$call": function(path, args) {
+				var names = path.split(".");
+				var self = window;
+				for (var i = 0; i < names.length - 1; i++) {
+					self = self == null ? undefined : self[names[i]];
+				}
+				var fn = self == null ? undefined : self[names[names.length - 1]];
+				if (typeof fn !== "function") throw new Error(path + " is not a function");
+				return fn.apply(self, args || []);
+			},
+			"$stream": function(id, value) {
+				var p = RPC.pending[id];
+				if (p && p.next) p.next(value);
+			},
+		},
+		send: function(msg) {
+			msg.jsonrpc = RPC.version;
+			window.external.invoke(JSON.stringify(msg));
+		},
+		call: function(method, params, signal) {
+			if (signal && signal.aborted) {
+				return Promise.reject(RPC.abortError(signal));
+			}
+			var id = RPC.nextSeq++;
+			var promise = new Promise(function(resolve, reject) {
+				RPC.pending[id] = { resolve: resolve, reject: reject };
+			});
+			if (signal) {
+				signal.addEventListener("abort", function() {
+					var p = RPC.pending[id];
+					if (!p) return;
+					delete RPC.pending[id];
+					RPC.notify("$cancel", [id]);
+					p.reject(RPC.abortError(signal));
+				});
+			}
+			RPC.send({ id: id, method: method, params: params || [] });
+			return promise;
+		},
+		// stream 调用流式返回的 Go 函数，返回异步迭代器，
+		// 每消费一半缓存的数据就通知 Go 继续发送，停止迭代时取消 Go 函数的执行
+		stream: function(method, params, signal) {
+			var id = RPC.nextSeq++;
+			var queue = [], waiters = [], done = false, error = null, consumed = 0;
+			var flush = function() {
+				while (waiters.length && (queue.length || done)) {
+					var w = waiters.shift();
+					if (queue.length) {
+						w.resolve({ value: queue.shift(), done: false });
+						consumed++;
+					} else if (error) {
+						w.reject(error);
+					} else {
+						w.resolve({ value: undefined, done: true });
+					}
+				}
+				if (!done && consumed >= ` + streamWindowJS + ` / 2) {
+					RPC.notify("$pull", [id, consumed]);
+					consumed = 0;
+				}
+			};
+			var finish = function(err) {
+				done = true;
+				error = err || null;
+				flush();
+			};
+			var cancel = function(err) {
+				if (!RPC.pending[id]) return;
+				delete RPC.pending[id];
+				RPC.notify("$cancel", [id]);
+				queue = [];
+				finish(err);
+			};
+			RPC.pending[id] = {
+				next: function(value) { queue.push(value); flush(); },
+				resolve: function() { finish(); },
+				reject: function(err) { finish(err); },
+			};
+			if (signal) {
+				if (signal.aborted) {
+					delete RPC.pending[id];
+					finish(RPC.abortError(signal));
+					return RPC.iterator(function() {}, flush, waiters);
+				}
+				signal.addEventListener("abort", function() { cancel(RPC.abortError(signal)); });
+			}
+			RPC.send({ id: id, method: method, params: params || [] });
+			RPC.notify("$pull", [id, ` + streamWindowJS + `]);
+			return RPC.iterator(cancel, flush, waiters);
+		},
+		iterator: function(cancel, flush, waiters) {
+			var it = {
+				next: function() {
+					return new Promise(function(resolve, reject) {
+						waiters.push({ resolve: resolve, reject: reject });
+						flush();
+					});
+				},
+				"return": function(value) {
+					cancel();
+					return Promise.resolve({ value: value, done: true });
+				},
+			};
+			it[Symbol.asyncIterator] = function() { return it; };
+			return it;
+		},
+		// 把调用参数中最后一个 AbortSignal 取出来，用于取消调用
+		args: function(args) {
+			args = Array.prototype.slice.call(args);
+			var last = args[args.length - 1];
+			var signal = typeof AbortSignal !== "undefined" && last instanceof AbortSignal ? args.pop() : undefined;
+			return { params: args, signal: signal };
+		},
+		abortError: function(signal) {
+			if (signal.reason !== undefined) return signal.reason;
+			var err = new Error("The operation was aborted.");
+			err.name = "AbortError";
+			return err;
+		},
+		notify: function(method, params) {
+			RPC.send({ method: method, params: params || [] });
+		},
+		toError: function(e) {
+			var err = new Error(e.message);
+			err.code = e.code;
+			err.data = e.data;
+			return err;
+		},
+		recv: function(msg) {
+			if (msg.method) {
+				var handler = RPC.handlers[msg.method];
+				var hasId = msg.id !== undefined && msg.id !== null;
+				if (!handler) {
+					if (hasId) RPC.send({ id: msg.id, error: { code: -32601, message: "method not found: " + msg.method } });
+					return;
+				}
+				var result = new Promise(function(resolve) { resolve(handler.apply(null, msg.params || [])); });
+				if (!hasId) {
+					result.catch(function() {});
+					return;
+				}
+				result.then(function(res) {
+					RPC.send({ id: msg.id, result: res === undefined ? null : res });
+				}, function(e) {
+					RPC.send({ id: msg.id, error: { code: -32000, message: String(e && e.message || e) } });
+				});
+				return;
+			}
+			var p = RPC.pending[msg.id];
+			if (!p) return;
+			delete RPC.pending[msg.id];
+			if (msg.error) {
+				p.reject(RPC.toError(msg.error));
+			} else {
+				p.resolve(msg.result);
+			}
+		},
+	};
+	RPC.notify("$ready");
+})()`
@@ -0,0 +1,51 @@
+package webview2
+
+// ScriptID 是通过 Init 注入的脚本的标识，用于 RemoveInit 移除脚本
+type ScriptID uint64
+
+// newScriptID 分配一个脚本 id，可以在任意线程调用，
+// 脚本要等 addScript 之后才真正注入
+func (b *bridge) newScriptID() ScriptID {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.nextScript++
+	return b.nextScript
+}
+
+// addScript 注入在每个页面创建时执行的脚本，需要在 UI 线程调用。
+// browser 异步返回脚本在浏览器中的 id，返回之前已经被 removeScript 的脚本会在返回后移除
+func (b *bridge) addScript(id ScriptID, js string) {
+	b.m.Lock()
+	b.scripts[id] = ""
+	b.m.Unlock()
+	b.browser.AddScript(js, func(native string) {
+		b.m.Lock()
+		_, ok := b.scripts[id]
+		if ok {
+			b.scripts[id] = native
+		}
+		b.m.Unlock()
+		if !ok {
+			b.browser.RemoveScript(native)
+		}
+	})
+}
+
+// removeScript 移除通过 addScript 注入的脚本，之后打开的页面不再执行，需要在 UI 线程调用
+func (b *bridge) removeScript(id ScriptID) bool {
+	b.m.Lock()
+	native, ok := b.scripts[id]
+	delete(b.scripts, id)
+	b.m.Unlock()
+	if ok && native != "" {
+		b.browser.RemoveScript(native)
+	}
+	return ok
+}
+
+// init 注入脚本并返回脚本 id
+func (b *bridge) init(js string) ScriptID {
+	id := b.newScriptID()
+	b.addScript(id, js)
+	return id
+}
@@ -0,0 +1,169 @@
+package webview2
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// defaultStateID 是不是通过 App 创建的窗口保存状态时使用的 id
+const defaultStateID = "main"
+
+// Bounds 是屏幕上的矩形区域，单位为物理像素
+type Bounds struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// intersect 返回两个区域重叠部分的面积
+func (b Bounds) intersect(o Bounds) int {
+	w := minInt(b.X+b.Width, o.X+o.Width) - maxInt(b.X, o.X)
+	h := minInt(b.Y+b.Height, o.Y+o.Height) - maxInt(b.Y, o.Y)
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	return w * h
+}
+
+// WindowState 是 WebViewOptions.PersistState 保存的窗口状态
+type WindowState struct {
+	// Bounds 是窗口在普通状态（不是最大化、最小化或者全屏）下的位置和大小
+	Bounds
+	Maximized  bool `json:"maximized,omitempty"`
+	Fullscreen bool `json:"fullscreen,omitempty"`
+	// Monitor 是保存时窗口所在显示器的工作区域
+	Monitor Bounds `json:"monitor"`
+}
+
+// Clamp 把状态调整到当前连接着的显示器上，monitors 是各个显示器的工作区域，第一个为主显示器。
+// 窗口和某个显示器有重叠时移到重叠最多的显示器中，都没有重叠时移到保存时的显示器，
+// 该显示器已经断开时移到主显示器，窗口比显示器大时缩小到显示器的大小。
+// 状态无效或者没有显示器时返回 false
+func (s WindowState) Clamp(monitors []Bounds) (WindowState, bool) {
+	if s.Width <= 0 || s.Height <= 0 || len(monitors) == 0 {
+		return s, false
+	}
+	target, best := -1, 0
+	for i, m := range monitors {
+		if area := s.Bounds.intersect(m); area > best {
+			target, best = i, area
+		}
+	}
+	if target < 0 {
+		target = 0
+		for i, m := range monitors {
+			if m == s.Monitor {
+				target = i
+				break
+			}
+		}
+	}
+	m := monitors[target]
+	s.Width, s.Height = minInt(s.Width, m.Width), minInt(s.Height, m.Height)
+	s.X = minInt(maxInt(s.X, m.X), m.X+m.Width-s.Width)
+	s.Y = minInt(maxInt(s.Y, m.Y), m.Y+m.Height-s.Height)
+	s.Monitor = m
+	return s, true
+}
+
+// stateMu 保护所有的状态文件，同一个进程中的多个窗口可能同时保存状态
+var stateMu sync.Mutex
+
+// statePath 返回保存窗口状态的文件路径
+func statePath(dataPath string) string {
+	return filepath.Join(defaultDataPath(dataPath), "WindowState.json")
+}
+
+// loadStates 读取状态文件中所有窗口的状态，文件不存在时返回空的 map
+func loadStates(path string) (map[string]WindowState, error) {
+	states := map[string]WindowState{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// LoadWindowState 从 path 读取 id 对应的窗口状态，没有保存过时返回 false
+func LoadWindowState(path, id string) (WindowState, bool, error) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	states, err := loadStates(path)
+	if err != nil {
+		return WindowState{}, false, err
+	}
+	s, ok := states[id]
+	return s, ok, nil
+}
+
+// SaveWindowState 把 id 对应的窗口状态保存到 path，文件中其他窗口的状态不变，
+// 先写入临时文件再替换，保存到一半退出时不会损坏原来的文件
+func SaveWindowState(path, id string, s WindowState) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	states, err := loadStates(path)
+	if err != nil {
+		// 文件损坏时重新保存
+		states = map[string]WindowState{}
+	}
+	states[id] = s
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// windowState 是窗口保存和恢复状态的配置，没有开启 PersistState 时为 nil
+type windowState struct {
+	path string
+	id   string
+}
+
+// newWindowState 根据 WebViewOptions.PersistState 返回窗口状态的配置
+func newWindowState(options WebViewOptions) *windowState {
+	if !options.PersistState {
+		return nil
+	}
+	id := options.id
+	if id == "" {
+		id = defaultStateID
+	}
+	return &windowState{path: statePath(options.DataPath), id: id}
+}
+
+// restore 读取保存的状态并调整到 monitors 上，没有可用的状态时返回 false
+func (ws *windowState) restore(monitors []Bounds, l logger) (WindowState, bool) {
+	s, ok, err := LoadWindowState(ws.path, ws.id)
+	if err != nil {
+		l.Info("load window state failed:", err)
+		return s, false
+	}
+	if !ok {
+		return s, false
+	}
+	return s.Clamp(monitors)
+}
+
+func (ws *windowState) save(s WindowState, l logger) {
+	if err := SaveWindowState(ws.path, ws.id, s); err != nil {
+		l.Info("save window state failed:", err)
+	}
+}
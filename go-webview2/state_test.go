@@ -0,0 +1,163 @@
+package webview2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWindowStateClamp(t *testing.T) {
+	primary := Bounds{X: 0, Y: 0, Width: 1920, Height: 1040}
+	second := Bounds{X: 1920, Y: 0, Width: 1280, Height: 984}
+
+	tests := []struct {
+		name     string
+		state    WindowState
+		monitors []Bounds
+		want     WindowState
+		ok       bool
+	}{
+		{
+			name:     "inside",
+			state:    WindowState{Bounds: Bounds{X: 100, Y: 100, Width: 800, Height: 600}, Monitor: primary},
+			monitors: []Bounds{primary, second},
+			want:     WindowState{Bounds: Bounds{X: 100, Y: 100, Width: 800, Height: 600}, Monitor: primary},
+			ok:       true,
+		},
+		{
+			name:     "fully off-screen",
+			state:    WindowState{Bounds: Bounds{X: 5000, Y: 3000, Width: 800, Height: 600}, Monitor: second},
+			monitors: []Bounds{primary, second},
+			want:     WindowState{Bounds: Bounds{X: 2400, Y: 384, Width: 800, Height: 600}, Monitor: second},
+			ok:       true,
+		},
+		{
+			name:     "partly off-screen",
+			state:    WindowState{Bounds: Bounds{X: -200, Y: 900, Width: 800, Height: 600}, Monitor: primary},
+			monitors: []Bounds{primary, second},
+			want:     WindowState{Bounds: Bounds{X: 0, Y: 440, Width: 800, Height: 600}, Monitor: primary},
+			ok:       true,
+		},
+		{
+			name:     "moves to the monitor with the largest overlap",
+			state:    WindowState{Bounds: Bounds{X: 1800, Y: 100, Width: 800, Height: 600}, Monitor: primary},
+			monitors: []Bounds{primary, second},
+			want:     WindowState{Bounds: Bounds{X: 1920, Y: 100, Width: 800, Height: 600}, Monitor: second},
+			ok:       true,
+		},
+		{
+			name:     "larger than the monitor",
+			state:    WindowState{Bounds: Bounds{X: 1900, Y: -50, Width: 3000, Height: 2000}, Monitor: second},
+			monitors: []Bounds{primary, second},
+			want:     WindowState{Bounds: Bounds{X: 1920, Y: 0, Width: 1280, Height: 984}, Monitor: second},
+			ok:       true,
+		},
+		{
+			name:     "monitor removed",
+			state:    WindowState{Bounds: Bounds{X: 2000, Y: 100, Width: 800, Height: 600}, Monitor: second},
+			monitors: []Bounds{primary},
+			want:     WindowState{Bounds: Bounds{X: 1120, Y: 100, Width: 800, Height: 600}, Monitor: primary},
+			ok:       true,
+		},
+		{
+			name:     "maximized",
+			state:    WindowState{Bounds: Bounds{X: 5000, Y: 100, Width: 800, Height: 600}, Maximized: true, Monitor: second},
+			monitors: []Bounds{primary},
+			want:     WindowState{Bounds: Bounds{X: 1120, Y: 100, Width: 800, Height: 600}, Maximized: true, Monitor: primary},
+			ok:       true,
+		},
+		{
+			name:     "fullscreen",
+			state:    WindowState{Bounds: Bounds{X: 100, Y: 100, Width: 800, Height: 600}, Fullscreen: true, Monitor: primary},
+			monitors: []Bounds{primary},
+			want:     WindowState{Bounds: Bounds{X: 100, Y: 100, Width: 800, Height: 600}, Fullscreen: true, Monitor: primary},
+			ok:       true,
+		},
+		{
+			name:     "empty size",
+			state:    WindowState{Bounds: Bounds{X: 100, Y: 100}, Monitor: primary},
+			monitors: []Bounds{primary},
+			want:     WindowState{Bounds: Bounds{X: 100, Y: 100}, Monitor: primary},
+		},
+		{
+			name:  "no monitors",
+			state: WindowState{Bounds: Bounds{X: 100, Y: 100, Width: 800, Height: 600}, Monitor: primary},
+			want:  WindowState{Bounds: Bounds{X: 100, Y: 100, Width: 800, Height: 600}, Monitor: primary},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.state.Clamp(tt.monitors)
+			if ok != tt.ok {
+				t.Fatalf("Clamp ok = %v, want %v", ok, tt.ok)
+			}
+			if got != tt.want {
+				t.Errorf("Clamp = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "WindowState.json")
+	main := WindowState{Bounds: Bounds{X: 10, Y: 20, Width: 800, Height: 600}, Maximized: true, Monitor: Bounds{Width: 1920, Height: 1040}}
+	settings := WindowState{Bounds: Bounds{X: 30, Y: 40, Width: 400, Height: 300}, Fullscreen: true}
+
+	if err := SaveWindowState(path, "main", main); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveWindowState(path, "settings", settings); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+
+	for id, want := range map[string]WindowState{"main": main, "settings": settings} {
+		got, ok, err := LoadWindowState(path, id)
+		if err != nil || !ok {
+			t.Fatalf("LoadWindowState(%q) = %v, %v", id, ok, err)
+		}
+		if got != want {
+			t.Errorf("LoadWindowState(%q) = %+v, want %+v", id, got, want)
+		}
+	}
+
+	if _, ok, err := LoadWindowState(path, "other"); err != nil || ok {
+		t.Errorf("LoadWindowState(other) = %v, %v, want false, nil", ok, err)
+	}
+}
+
+func TestLoadWindowStateMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "WindowState.json")
+	s, ok, err := LoadWindowState(path, "main")
+	if err != nil || ok {
+		t.Fatalf("LoadWindowState = %v, %v, want false, nil", ok, err)
+	}
+	if s != (WindowState{}) {
+		t.Errorf("LoadWindowState = %+v, want zero state", s)
+	}
+}
+
+func TestWindowStateCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "WindowState.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := LoadWindowState(path, "main"); err == nil || ok {
+		t.Fatalf("LoadWindowState = %v, %v, want an error", ok, err)
+	}
+
+	// 文件损坏时重新保存
+	want := WindowState{Bounds: Bounds{X: 1, Y: 2, Width: 300, Height: 200}}
+	if err := SaveWindowState(path, "main", want); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := LoadWindowState(path, "main")
+	if err != nil || !ok {
+		t.Fatalf("LoadWindowState = %v, %v", ok, err)
+	}
+	if got != want {
+		t.Errorf("LoadWindowState = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,119 @@
+package webview2
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// streamWindow 是 JS 端默认缓存的流式数据条数，Go 端发送的数据超过这个数量后，
+// 需要等 JS 端消费之后才能继续发送
+const streamWindow = 16
+
+var emitterType = reflect.TypeOf((*Emitter)(nil)).Elem()
+
+// Emitter 用于绑定的 Go 函数向 JS 流式返回多个值，
+// 绑定函数参数中有 Emitter 时（在 context.Context 参数之后），JS 调用返回异步迭代器，
+// 可以通过 for await (const v of fn()) 依次获取 Emit 的值，函数返回后迭代结束
+type Emitter interface {
+	// Emit 向 JS 发送一个值，JS 端来不及消费时会阻塞，
+	// 调用被取消（页面跳转、JS 停止迭代等）时返回错误，此时应该停止发送
+	Emit(v interface{}) error
+}
+
+// streamEmitter 以通知的形式把值发给 JS，按 JS 端授予的额度控制发送速度
+type streamEmitter struct {
+	b      *bridge
+	id     json.RawMessage
+	ctx    context.Context
+	m      sync.Mutex
+	credit int
+	wake   chan struct{}
+}
+
+func newStreamEmitter(b *bridge, id json.RawMessage, ctx context.Context) *streamEmitter {
+	return &streamEmitter{b: b, id: id, ctx: ctx, wake: make(chan struct{}, 1)}
+}
+
+func (s *streamEmitter) Emit(v interface{}) error {
+	for {
+		if err := s.ctx.Err(); err != nil {
+			return err
+		}
+		s.m.Lock()
+		if s.credit > 0 {
+			s.credit--
+			s.m.Unlock()
+			break
+		}
+		s.m.Unlock()
+		select {
+		case <-s.wake:
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		}
+	}
+	n, err := NewRPCNotification("$stream", s.id, v)
+	if err != nil {
+		return err
+	}
+	s.b.send(n)
+	return nil
+}
+
+// grant 增加可发送的数量，由 JS 端消费数据后通知
+func (s *streamEmitter) grant(n int) {
+	s.m.Lock()
+	s.credit += n
+	s.m.Unlock()
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// drain 把绑定函数返回的 channel 中的值依次发送给 JS，直到 channel 关闭或者调用被取消
+func (s *streamEmitter) drain(ch reflect.Value) error {
+	if ch.IsNil() {
+		return nil
+	}
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: ch},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(s.ctx.Done())},
+	}
+	for {
+		i, v, ok := reflect.Select(cases)
+		if i == 1 {
+			return s.ctx.Err()
+		}
+		if !ok {
+			return nil
+		}
+		if err := s.Emit(v.Interface()); err != nil {
+			return err
+		}
+	}
+}
+
+// pullStream 处理 JS 端的 $pull 通知，参数为请求 id 和可以继续发送的数量，只处理 source 发起的调用
+func (b *bridge) pullStream(d *RPCMessage, source string) {
+	params, err := d.ParamList()
+	if err != nil || len(params) < 2 {
+		return
+	}
+	var n int
+	if err := json.Unmarshal(params[1], &n); err != nil {
+		return
+	}
+	id := string(params[0])
+	if b.call(id, source) == nil {
+		return
+	}
+	b.m.Lock()
+	s, ok := b.streams[id]
+	b.m.Unlock()
+	if ok {
+		s.grant(n)
+	}
+}
@@ -0,0 +1,131 @@
+package webview2
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeadlessStream(t *testing.T) {
+	h := NewHeadless(WebViewOptions{})
+	h.Bind("count", func(em Emitter, n int) error {
+		for i := 1; i <= n; i++ {
+			if err := em.Emit(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	h.Bind("letters", func() <-chan string {
+		ch := make(chan string, 3)
+		ch <- "a"
+		ch <- "b"
+		ch <- "c"
+		close(ch)
+		return ch
+	})
+	stopped := make(chan struct{})
+	h.Bind("forever", func(ctx context.Context, em Emitter) error {
+		defer close(stopped)
+		for i := 0; ; i++ {
+			if err := em.Emit(i); err != nil {
+				return err
+			}
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	collect := func(method string, params ...interface{}) string {
+		var got []string
+		err := h.Stream(ctx, method, func(v json.RawMessage) bool {
+			got = append(got, string(v))
+			return true
+		}, params...)
+		if err != nil {
+			t.Errorf("%s: %v", method, err)
+		}
+		return strings.Join(got, ",")
+	}
+	// 超过 streamWindow 的数据需要页面继续拉取
+	if got, want := collect("count", streamWindow+4), "1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20"; got != want {
+		t.Errorf("count = %s, want %s", got, want)
+	}
+	if got, want := collect("letters"), `"a","b","c"`; got != want {
+		t.Errorf("letters = %s, want %s", got, want)
+	}
+
+	n := 0
+	err := h.Stream(ctx, "forever", func(v json.RawMessage) bool {
+		n++
+		return n < 3
+	})
+	if err != nil || n != 3 {
+		t.Errorf("forever = %d, %v", n, err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("stream was not canceled after the page stopped iterating")
+	}
+}
+
+// 页面停止迭代后，Go 函数被取消，正在发送的数据不会阻塞
+func TestHeadlessStreamStop(t *testing.T) {
+	h := NewHeadless(WebViewOptions{})
+	stopped := make(chan struct{})
+	h.Bind("ticks", func(ctx context.Context) <-chan int {
+		ch := make(chan int)
+		go func() {
+			defer close(stopped)
+			defer close(ch)
+			for i := 0; ; i++ {
+				select {
+				case ch <- i:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		return ch
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var got []string
+	err := h.Stream(ctx, "ticks", func(v json.RawMessage) bool {
+		got = append(got, string(v))
+		return len(got) < 2
+	})
+	if err != nil || len(got) != 2 || got[0] != "0" || got[1] != "1" {
+		t.Errorf("Stream = %v, %v", got, err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("producer was not canceled after the page stopped iterating")
+	}
+}
+
+func TestHeadlessStreamCanceled(t *testing.T) {
+	h := NewHeadless(WebViewOptions{})
+	h.Bind("wait", func(ctx context.Context, em Emitter) error {
+		if err := em.Emit("first"); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	var got []string
+	err := h.Stream(ctx, "wait", func(v json.RawMessage) bool {
+		got = append(got, string(v))
+		cancel()
+		return true
+	})
+	if err != context.Canceled || len(got) != 1 {
+		t.Errorf("Stream = %v, %v, want one value and %v", got, err, context.Canceled)
+	}
+}
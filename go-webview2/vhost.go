@@ -0,0 +1,57 @@
+package webview2
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// HostAccess 是虚拟主机中的文件被其他来源的页面访问时的限制，
+// 和 COREWEBVIEW2_HOST_RESOURCE_ACCESS_KIND 一致，同源的页面总是可以访问
+type HostAccess int
+
+const (
+	// HostAccessDeny 禁止其他来源的页面访问，是默认值
+	HostAccessDeny HostAccess = iota
+	// HostAccessAllow 允许其他来源的页面访问，包括 fetch 和 XMLHttpRequest
+	HostAccessAllow
+	// HostAccessDenyCORS 允许其他来源的页面作为图片、脚本等子资源加载，但是禁止需要 CORS 的访问
+	HostAccessDenyCORS
+)
+
+// VirtualHost 把一个 https 主机名映射到本地目录，如 app.example 映射到 ./dist 后，
+// 页面可以通过 https://app.example/index.html 访问 ./dist/index.html，
+// 和普通网站一样有稳定的来源，可以正常使用 cookie、localStorage 等
+type VirtualHost struct {
+	// Folder 是本地目录，相对路径相对于当前工作目录
+	Folder string
+	Access HostAccess
+}
+
+// virtualHostFolder 返回虚拟主机目录的绝对路径，目录不存在时返回错误
+func virtualHostFolder(host string, vh VirtualHost) (string, error) {
+	if host == "" {
+		return "", errors.New("virtual host name is empty")
+	}
+	if vh.Folder == "" {
+		return "", errors.New("virtual host " + host + " folder is empty")
+	}
+	folder, err := filepath.Abs(vh.Folder)
+	if err != nil {
+		return "", err
+	}
+	fi, err := os.Stat(folder)
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsDir() {
+		return "", errors.New("virtual host " + host + " folder is not a directory: " + folder)
+	}
+	return folder, nil
+}
+
+// virtualHostHandler 用 net/http 模拟虚拟主机，用于 Headless
+func virtualHostHandler(folder string) http.Handler {
+	return http.FileServer(http.Dir(folder))
+}
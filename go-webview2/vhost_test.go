@@ -0,0 +1,59 @@
+package webview2
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHeadlessVirtualHost(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.js"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "app.js")
+
+	h := NewHeadless(WebViewOptions{})
+	tests := []struct {
+		name string
+		host string
+		vh   VirtualHost
+	}{
+		{"empty host", "", VirtualHost{Folder: dir}},
+		{"empty folder", "app.example", VirtualHost{}},
+		{"missing folder", "app.example", VirtualHost{Folder: filepath.Join(dir, "missing")}},
+		{"not a directory", "app.example", VirtualHost{Folder: file}},
+	}
+	for _, tt := range tests {
+		if err := h.AddVirtualHost(tt.host, tt.vh); err == nil {
+			t.Errorf("%s: AddVirtualHost succeeded", tt.name)
+		}
+	}
+	if got := h.VirtualHosts(); len(got) != 0 {
+		t.Errorf("VirtualHosts() = %v after failed AddVirtualHost", got)
+	}
+
+	if err := h.AddVirtualHost("app.example", VirtualHost{Folder: dir, Access: HostAccessAllow}); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.VirtualHosts()["app.example"]; got.Folder != dir || got.Access != HostAccessAllow {
+		t.Errorf("VirtualHosts() = %v", h.VirtualHosts())
+	}
+	if code, body := fetch(t, h, "https://app.example/app.js"); code != http.StatusOK || body != "hello" {
+		t.Errorf("fetch = %d %q", code, body)
+	}
+	if code, _ := fetch(t, h, "https://app.example/missing.html"); code != http.StatusNotFound {
+		t.Errorf("fetch missing = %d, want %d", code, http.StatusNotFound)
+	}
+
+	if err := h.RemoveVirtualHost(""); err == nil {
+		t.Error("RemoveVirtualHost accepted an empty host")
+	}
+	if err := h.RemoveVirtualHost("app.example"); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := fetch(t, h, "https://app.example/app.js"); code != 0 {
+		t.Errorf("fetch after RemoveVirtualHost = %d", code)
+	}
+}
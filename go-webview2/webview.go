@@ -6,9 +6,9 @@ package webview2
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"log"
-	"reflect"
-	"strconv"
+	"net/http"
 	"sync"
 	"unsafe"
 
@@ -74,68 +74,45 @@ type navigationCompletedArg struct {
 	Success bool
 }
 
-type browser interface {
-	Embed(hwnd uintptr) bool
-	Resize()
-	Navigate(url string) error
-	NavigateToString(htmlContent string)
-	Init(script string)
-	Eval(script string)
-	NotifyParentWindowPositionChanged() error
-	Focus()
-}
-
 type webview struct {
 	hwnd        uintptr
-	mainthread  uintptr
 	browser     browser
 	autofocus   bool
 	hideOnClose bool
 	maxsz       w32.Point
 	minsz       w32.Point
 	m           sync.Mutex
-	bindings    map[string]interface{}
+	bridge      *bridge
+	resources   *resourceRouter
+	cache       *OfflineCache
 	dispatchq   []func()
 	logger      logger
-}
-
-type logger interface {
-	Info(v ...interface{})
-}
-
-type WindowOptions struct {
-	Title     string
-	Width     uint
-	Height    uint
-	IconId    uint
-	Icon      string
-	Center    bool
-	Frameless bool
-}
-
-type WebViewOptions struct {
-	Window            unsafe.Pointer
-	StartURL          string
-	FallbackPage      string
-	StartHTML         string
-	HideWindowOnClose bool
-
-	// if true, enable context menu and chrome devtools
-	Debug bool
-
-	// DataPath specifies the datapath for the WebView2 runtime to use for the
-	// browser instance.
-	DataPath string
-
-	// AutoFocus will try to keep the WebView2 widget focused when the window
-	// is focused.
-	AutoFocus bool
-
-	Logger logger
-
-	// WindowOptions customizes the window that is created to embed the
-	// WebView2 widget.
-	WindowOptions WindowOptions
+	options     WebViewOptions
+	policy      *NavigationPolicy
+	fallback    *fallback
+
+	// events 处理 Window 发给窗口的操作，destroyed 在窗口销毁后调用，没有设置时退出消息循环，
+	// openWindow 在新的桌面窗口中打开页面，没有设置时在新的线程中创建窗口
+	events     func(e *winEvent)
+	destroyed  func()
+	openWindow func(opts WebViewOptions)
+	// result 是页面通过 window.desktop.close(result) 传入的值，用于 ShowModal
+	result *windowResult
+	// fullscreen 表示窗口是否全屏，savedStyle 和 savedRect 是全屏前的样式和位置
+	fullscreen bool
+	savedStyle uintptr
+	savedRect  w32.Rect
+	// state 保存和恢复窗口状态，没有开启 PersistState 时为 nil，
+	// normalRect 是窗口最后一次在普通状态下的位置和大小
+	state      *windowState
+	normalRect w32.Rect
+	// iconic 表示窗口当前是否最小化，用于触发 OnMinimize 和 OnRestore
+	iconic bool
+	// frame 是标题栏和边框的设置，无边框窗口由 WM_NCHITTEST 和页面中的 frameRuntime 处理拖动和调整大小
+	frame *frame
+
+	navigationEvents
+	windowEvents
 }
 
 // New creates a new webview in a new window.
@@ -150,25 +127,55 @@ func NewWindow(debug bool, window unsafe.Pointer) WebView {
 
 // NewWithOptions creates a new webview using the provided options.
 func NewWithOptions(options WebViewOptions) WebView {
+	w, err := newWebview(options)
+	if err != nil {
+		return nil
+	}
+	return w
+}
+
+// newWebview 创建 webview，失败时返回原因
+func newWebview(options WebViewOptions) (*webview, error) {
 	w := &webview{}
 	w.logger = options.Logger
-	w.bindings = map[string]interface{}{}
+	if w.logger == nil {
+		w.logger = discardLogger{}
+	}
 	w.autofocus = options.AutoFocus
 	w.hideOnClose = options.HideWindowOnClose
+	w.resources = &resourceRouter{}
+	w.result = newWindowResult()
+	w.frame = newFrame(options.WindowOptions)
 
 	chromium := edge.NewChromium()
-	chromium.MessageCallback = w.msgcb
+	w.bridge = newBridge(chromium, w.Dispatch, w.logger)
+	w.bridge.origins = options.AllowedOrigins
+	w.bridge.closeWindow = w.closeWithResult
+	w.bridge.frame = w.frameHit
+	w.bridge.regions = w.frame.setRegions
+	w.windowEvents.send = w.bridge.windowEvent
+	chromium.MessageSourceCallback = w.bridge.msgcb
 	chromium.DataPath = options.DataPath
 	chromium.SetPermission(edge.CoreWebView2PermissionKindClipboardRead, edge.CoreWebView2PermissionStateAllow)
 
 	if ok := chromium.CheckOrInstallWv2(); !ok {
-		return nil
+		return nil, errors.New("WebView2 Runtime is not installed and could not be installed")
 	}
 
 	w.browser = chromium
-	w.mainthread, _, _ = w32.Kernel32GetCurrentThreadID.Call()
+	w.options = options
+	w.state = newWindowState(options)
+	w.listenNavigation(chromium)
+	if options.NavigationPolicy != nil {
+		w.policy = options.NavigationPolicy
+		w.policy.enforce(&w.navigationEvents, w.openExternal)
+		chromium.OnNewWindowRequested(w.newWindowRequested)
+	}
 	if !w.CreateWithOptions(options.WindowOptions) {
-		return nil
+		return nil, errors.New("create window or WebView2 controller failed")
+	}
+	if options.Modal && options.Parent != nil {
+		options.Parent.setEnabled(false)
 	}
 
 	settings, err := chromium.GetSettings()
@@ -190,130 +197,161 @@ func NewWithOptions(options WebViewOptions) WebView {
 		}
 	}
 
-	if options.FallbackPage != "" {
-		w.SetFallbackPage(options.FallbackPage)
+	if fb := fallbackOptions(options); fb != nil {
+		if err := w.SetFallback(*fb); err != nil {
+			w.logger.Info("invalid fallback page:", err)
+		}
+	}
+
+	for host, vh := range options.VirtualHosts {
+		if err := w.AddVirtualHost(host, vh); err != nil {
+			w.logger.Info("add virtual host "+host+" failed:", err)
+		}
+	}
+
+	chromium.WebResourceRequestedCallback = w.serveResource
+	app := appHandler(options)
+	if app != nil {
+		w.Handle(AppOrigin+"/*", app)
+	}
+	if w.cache, err = newCache(options); err != nil {
+		w.logger.Info("open offline cache failed:", err)
+	} else if w.cache != nil {
+		// 只拦截匹配缓存规则的请求
+		patterns := w.cache.patterns()
+		w.resources.useFor(w.cache.Middleware, patterns)
+		for _, pattern := range patterns {
+			chromium.AddWebResourceRequestedFilter(pattern, edge.COREWEBVIEW2_WEB_RESOURCE_CONTEXT_ALL)
+		}
+		w.cache.listen(&w.navigationEvents, func(url string) {
+			w.Dispatch(func() { w.Navigate(url) })
+		})
+		chromium.OnWebResourceResponseReceived(w.responseReceived)
 	}
 
 	if options.StartURL != "" {
 		w.Navigate(options.StartURL)
 	} else if options.StartHTML != "" {
 		w.SetHtml(options.StartHTML)
+	} else if app != nil {
+		w.Navigate(AppOrigin + "/")
 	}
 
-	return w
+	return w, nil
 }
 
-type rpcMessage struct {
-	ID     int               `json:"id"`
-	Method string            `json:"method"`
-	Params []json.RawMessage `json:"params"`
+// frameHits 是 frame 中的区域对应的 WM_NCHITTEST 返回值
+var frameHits = map[string]uintptr{
+	frameClient:      w32.HTClient,
+	frameCaption:     w32.HTCaption,
+	frameMaxButton:   w32.HTMaxButton,
+	frameTop:         w32.HTTop,
+	frameBottom:      w32.HTBottom,
+	frameLeft:        w32.HTLeft,
+	frameRight:       w32.HTRight,
+	frameTopLeft:     w32.HTTopLeft,
+	frameTopRight:    w32.HTTopRight,
+	frameBottomLeft:  w32.HTBottomLeft,
+	frameBottomRight: w32.HTBottomRight,
 }
 
-func jsString(v interface{}) string { b, _ := json.Marshal(v); return string(b) }
-
-func (w *webview) msgcb(msg string) {
-	d := rpcMessage{}
-	if err := json.Unmarshal([]byte(msg), &d); err != nil {
-		log.Printf("invalid RPC message: %v", err)
-		return
-	}
-
-	id := strconv.Itoa(d.ID)
-	if res, err := w.callbinding(d); err != nil {
-		w.Dispatch(func() {
-			w.Eval("window._rpc[" + id + "].reject(" + jsString(err.Error()) + "); window._rpc[" + id + "] = undefined")
-		})
-	} else if b, err := json.Marshal(res); err != nil {
-		w.Dispatch(func() {
-			w.Eval("window._rpc[" + id + "].reject(" + jsString(err.Error()) + "); window._rpc[" + id + "] = undefined")
-		})
-	} else {
-		w.Dispatch(func() {
-			w.Eval("window._rpc[" + id + "].resolve(" + string(b) + "); window._rpc[" + id + "] = undefined")
-		})
-	}
+// frameHit 处理页面中按下标题栏或者最大化按钮。WebView 覆盖了这些区域，系统不会把按下交给窗口，
+// 这里按窗口自己的 WM_NCHITTEST 的结果处理：标题栏交给系统移动窗口，这样拖到屏幕边缘时同样可以分屏，
+// 双击标题栏和按下最大化按钮时最大化或者还原窗口。x、y 是页面中的位置，这里使用鼠标的屏幕坐标
+func (w *webview) frameHit(x, y float64) {
+	// 消息不在 UI 线程处理，ReleaseCapture 必须在 UI 线程调用
+	w.Dispatch(func() {
+		if w.fullscreen {
+			return
+		}
+		var pt w32.Point
+		_, _, _ = w32.User32GetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
+		ht, _, _ := w32.User32SendMessage.Call(w.hwnd, w32.WMNCHitTest, 0, makeLParam(pt.X, pt.Y))
+		switch {
+		case ht == w32.HTMaxButton, ht == w32.HTCaption && w.frame.caption():
+			if w.IsMaximized() {
+				w.Restore()
+			} else {
+				w.Maximize()
+			}
+		case ht == w32.HTCaption:
+			_, _, _ = w32.User32ReleaseCapture.Call()
+			_, _, _ = w32.User32PostMessageW.Call(w.hwnd, w32.WMNCLButtonDown, ht, makeLParam(pt.X, pt.Y))
+		}
+	})
 }
 
-func (w *webview) callbinding(d rpcMessage) (interface{}, error) {
-	w.m.Lock()
-	f, ok := w.bindings[d.Method]
-	w.m.Unlock()
-	if !ok {
-		return nil, nil
+// hitTest 处理 WM_NCHITTEST，lp 为鼠标的屏幕坐标。无边框窗口的边缘没有被 WebView 覆盖，返回调整大小的位置，
+// 客户区中再根据页面通过 $regions 通知的区域返回 HTCAPTION 和 HTMAXBUTTON
+func (w *webview) hitTest(wp, lp uintptr) uintptr {
+	if !w.frame.frameless {
+		r, _, _ := w32.User32DefWindowProcW.Call(w.hwnd, w32.WMNCHitTest, wp, lp)
+		if r != w32.HTClient {
+			return r
+		}
 	}
-
-	v := reflect.ValueOf(f)
-	isVariadic := v.Type().IsVariadic()
-	numIn := v.Type().NumIn()
-	if (isVariadic && len(d.Params) < numIn-1) || (!isVariadic && len(d.Params) != numIn) {
-		return nil, errors.New("function arguments mismatch")
+	if w.fullscreen {
+		return w32.HTClient
 	}
-	args := []reflect.Value{}
-	for i := range d.Params {
-		var arg reflect.Value
-		if isVariadic && i >= numIn-1 {
-			arg = reflect.New(v.Type().In(numIn - 1).Elem())
-		} else {
-			arg = reflect.New(v.Type().In(i))
-		}
-		if err := json.Unmarshal(d.Params[i], arg.Interface()); err != nil {
-			return nil, err
+	pt := w32.Point{X: int32(int16(lp & 0xffff)), Y: int32(int16((lp >> 16) & 0xffff))}
+	dpi := w.dpi()
+	if w.frame.frameless && !w.IsMaximized() {
+		var r w32.Rect
+		_, _, _ = w32.User32GetWindowRect.Call(w.hwnd, uintptr(unsafe.Pointer(&r)))
+		border := w.frame.border * dpi / 96
+		hit := w.frame.hitTest(int(pt.X-r.Left), int(pt.Y-r.Top), int(r.Right-r.Left), int(r.Bottom-r.Top), border)
+		if hit != frameClient {
+			return frameHits[hit]
 		}
-		args = append(args, arg.Elem())
 	}
+	_, _, _ = w32.User32ScreenToClient.Call(w.hwnd, uintptr(unsafe.Pointer(&pt)))
+	inset := w.inset()
+	scale := float64(dpi) / 96
+	return frameHits[w.frame.regionAt(float64(int(pt.X)-inset)/scale, float64(int(pt.Y)-inset)/scale)]
+}
 
-	errorType := reflect.TypeOf((*error)(nil)).Elem()
-	res := v.Call(args)
-	switch len(res) {
-	case 0:
-		// No results from the function, just return nil
-		return nil, nil
-
-	case 1:
-		// One result may be a value, or an error
-		if res[0].Type().Implements(errorType) {
-			if res[0].Interface() != nil {
-				return nil, res[0].Interface().(error)
-			}
-			return nil, nil
-		}
-		return res[0].Interface(), nil
-
-	case 2:
-		// Two results: first one is value, second is error
-		if !res[1].Type().Implements(errorType) {
-			return nil, errors.New("second return value must be an error")
-		}
-		if res[1].Interface() == nil {
-			return res[0].Interface(), nil
-		}
-		return res[0].Interface(), res[1].Interface().(error)
+// dpi 返回窗口所在显示器的 DPI，获取失败时为 96
+func (w *webview) dpi() int {
+	dpi, _, _ := w32.User32GetDpiForWindow.Call(w.hwnd)
+	if dpi == 0 {
+		return 96
+	}
+	return int(dpi)
+}
 
-	default:
-		return nil, errors.New("unexpected number of return values")
+// inset 返回 WebView 到窗口客户区边缘的距离，单位为物理像素。无边框窗口没有最大化和全屏时
+// 空出可以调整大小的边缘，这部分不被 WebView 覆盖，由窗口自己的 WM_NCHITTEST 处理
+func (w *webview) inset() int {
+	if !w.frame.frameless || w.fullscreen || w.IsMaximized() {
+		return 0
 	}
+	return w.frame.border * w.dpi() / 96
 }
 
-// 实现通过 css 样式定义 -webkit-app-region: drag 可拖动窗口
-func (w *webview) appRegion() {
-	w.Init(`window.addEventListener('DOMContentLoaded', () => {
-    document.body.addEventListener('mousedown', evt => {
-        const { target } = evt;
-        const appRegion = getComputedStyle(target)['-webkit-app-region'];
+// resize 按窗口大小和 inset 调整 WebView 的大小
+func (w *webview) resize() {
+	w.browser.SetInset(w.inset())
+	w.browser.Resize()
+}
 
-        if (appRegion === 'drag') {
-            window.__drapAppRegion();
-            evt.preventDefault();
-            evt.stopPropagation();
-        }
-    });
-});`)
-	w.Bind("__drapAppRegion", w.dragAppRegion)
+// calcSize 处理无边框窗口的 WM_NCCALCSIZE，整个窗口都作为内容区域，
+// 最大化时系统会把窗口扩大边框的宽度，需要减去，否则页面的边缘会超出屏幕
+func (w *webview) calcSize(lp uintptr) {
+	if !w.IsMaximized() || w.fullscreen {
+		return
+	}
+	r := (*w32.Rect)(unsafe.Pointer(lp))
+	padding, _, _ := w32.User32GetSystemMetrics.Call(w32.SM_CXPADDEDBORDER)
+	cx, _, _ := w32.User32GetSystemMetrics.Call(w32.SM_CXFRAME)
+	cy, _, _ := w32.User32GetSystemMetrics.Call(w32.SM_CYFRAME)
+	r.Left += int32(cx + padding)
+	r.Right -= int32(cx + padding)
+	r.Top += int32(cy + padding)
+	r.Bottom -= int32(cy + padding)
 }
 
-func (w *webview) dragAppRegion() {
-	w32.User32ReleaseCapture.Call(w.hwnd)
-	w32.User32PostMessageW.Call(w.hwnd, 161, 2, 0)
+func makeLParam(x, y int32) uintptr {
+	return uintptr(uint16(x)) | uintptr(uint16(y))<<16
 }
 
 func (w *webview) updateWinForDpi(hwnd uintptr) {
@@ -345,28 +383,76 @@ func (w *webview) wndproc(hwnd, msg, wp, lp uintptr) uintptr {
 		case w32.WMCreate, w32.WMDpiChanged:
 			w.updateWinForDpi(hwnd)
 		case w32.WMMove, w32.WMMoving:
+			w.trackNormalRect()
 			_ = w.browser.NotifyParentWindowPositionChanged()
+			if r, _, _ := w32.User32IsIconic.Call(hwnd); msg == w32.WMMove && r == 0 {
+				x, y := w.GetPosition()
+				w.moved(x, y)
+			}
+		case w32.WMNCCalcSize:
+			if !w.frame.frameless || wp == 0 {
+				r, _, _ := w32.User32DefWindowProcW.Call(hwnd, msg, wp, lp)
+				return r
+			}
+			w.calcSize(lp)
+		case w32.WMNCHitTest:
+			return w.hitTest(wp, lp)
 		case w32.WMNCLButtonDown:
 			_, _, _ = w32.User32SetFocus.Call(w.hwnd)
 			r, _, _ := w32.User32DefWindowProcW.Call(hwnd, msg, wp, lp)
 			return r
 		case w32.WMSize:
-			w.browser.Resize()
+			w.trackNormalRect()
+			w.resize()
+			w.sizeChanged(wp, lp)
 		case w32.WMActivate:
-			if wp == w32.WAInactive {
+			if wp&0xffff == w32.WAInactive {
+				w.blurred()
 				break
 			}
+			w.focused()
 			if w.autofocus {
 				w.browser.Focus()
 			}
 		case w32.WMClose:
-			if w.hideOnClose {
+			if !w.closeRequested() {
+				if wp == closeDestroy {
+					// 取消关闭时丢弃页面传入的值，之后用户直接关闭窗口时返回 nil
+					w.result.set(nil)
+				}
+				break
+			}
+			if w.hideOnClose && !w.options.Modal && wp != closeDestroy {
 				w.Hide()
 			} else {
+				w.enableParent()
 				_, _, _ = w32.User32DestroyWindow.Call(hwnd)
 			}
 		case w32.WMDestroy:
-			w.Terminate()
+			w.saveState()
+			w.enableParent()
+			w.bridge.close()
+			w.result.closed()
+			// 唤醒 ShowModal 中的消息循环，让它在没有其他消息时也能返回
+			thread, _, _ := w32.Kernel32GetCurrentThreadID.Call()
+			_, _, _ = w32.User32PostThreadMessageW.Call(thread, w32.WMNull, 0, 0)
+			if w.destroyed != nil {
+				w.destroyed()
+			} else {
+				w.Terminate()
+			}
+		case w32.WMApp:
+			w.m.Lock()
+			q := append([]func(){}, w.dispatchq...)
+			w.dispatchq = []func(){}
+			w.m.Unlock()
+			for _, v := range q {
+				v()
+			}
+		case wmWindowEvent:
+			if w.events != nil {
+				w.events((*winEvent)(unsafe.Pointer(wp)))
+			}
 		case w32.WMGetMinMaxInfo:
 			lpmmi := (*w32.MinMaxInfo)(unsafe.Pointer(lp))
 			if w.maxsz.X > 0 && w.maxsz.Y > 0 {
@@ -414,6 +500,8 @@ func (w *webview) CreateWithOptions(opts WindowOptions) bool {
 		HIcon:         windows.Handle(icon),
 		HIconSm:       windows.Handle(icon),
 		LpfnWndProc:   windows.NewCallback(w.wndproc),
+		// 无边框窗口的边缘没有被 WebView 覆盖，使用窗口背景色绘制
+		HbrBackground: windows.Handle(w32.ColorWindow + 1),
 	}
 	_, _, _ = w32.User32RegisterClassExW.Call(uintptr(unsafe.Pointer(&wc)))
 
@@ -442,13 +530,33 @@ func (w *webview) CreateWithOptions(opts WindowOptions) bool {
 		posY = w32.CW_USEDEFAULT
 	}
 
+	// 恢复保存的状态，保存的位置和大小已经是物理像素，不需要再按 DPI 缩放
+	var restored WindowState
+	var hasState bool
+	if w.state != nil {
+		restored, hasState = w.state.restore(monitors(), w.logger)
+		if hasState {
+			posX, posY = uint(restored.X), uint(restored.Y)
+			windowWidth, windowHeight = uint(restored.Width), uint(restored.Height)
+		}
+	}
+
+	// 无边框窗口同样使用 WSOverlappedWindow，由 WM_NCCALCSIZE 去掉标题栏和边框，
+	// 保留样式后系统才会提供拖到屏幕边缘分屏、最小化动画等功能
 	var winSetting uintptr = w32.WSOverlappedWindow
-	if opts.Frameless {
-		winSetting = w32.WSPopupWindow | w32.WSMinimizeBox | w32.WSMaximizeBox | w32.WSSizeBox
+
+	var owner uintptr
+	if w.options.Parent != nil {
+		owner = w.options.Parent.nativeHandle()
+	}
+
+	var exStyle uintptr
+	if opts.AlwaysOnTop {
+		exStyle |= w32.WSExTopMost
 	}
 
 	w.hwnd, _, _ = w32.User32CreateWindowExW.Call(
-		0,
+		exStyle,
 		uintptr(unsafe.Pointer(className)),
 		uintptr(unsafe.Pointer(windowName)),
 		winSetting, // 0xCF0000, // WS_OVERLAPPEDWINDOW
@@ -456,27 +564,69 @@ func (w *webview) CreateWithOptions(opts WindowOptions) bool {
 		uintptr(posY),
 		uintptr(windowWidth),
 		uintptr(windowHeight),
-		0,
+		owner,
 		0,
 		uintptr(hinstance),
 		0,
 	)
 	setWindowContext(w.hwnd, w)
-	w.updateWinForDpi(w.hwnd)
+	if w.frame.frameless {
+		// 创建时 WM_NCCALCSIZE 还没有 context，需要重新计算一次
+		_, _, _ = w32.User32SetWindowPos.Call(w.hwnd, 0, 0, 0, 0, 0, w32.SWPNoMove|w32.SWPNoSize|w32.SWPNoZOrder|w32.SWPNoActivate|w32.SWPFrameChanged)
+	}
+	if !hasState {
+		w.updateWinForDpi(w.hwnd)
+	}
+	_, _, _ = w32.User32GetWindowRect.Call(w.hwnd, uintptr(unsafe.Pointer(&w.normalRect)))
 
-	_, _, _ = w32.User32ShowWindow.Call(w.hwnd, w32.SWShow)
-	_, _, _ = w32.User32UpdateWindow.Call(w.hwnd)
-	_, _, _ = w32.User32SetFocus.Call(w.hwnd)
+	// StartHidden 时不显示窗口，WebView2 可以嵌入到隐藏的窗口中，之后 Show 时页面已经准备好
+	if !opts.StartHidden {
+		show := uintptr(w32.SWShow)
+		if restored.Maximized {
+			show = w32.SWMaximize
+		}
+		_, _, _ = w32.User32ShowWindow.Call(w.hwnd, show)
+		_, _, _ = w32.User32UpdateWindow.Call(w.hwnd)
+		_, _, _ = w32.User32SetFocus.Call(w.hwnd)
+	}
 
 	if !w.browser.Embed(w.hwnd) {
 		return false
 	}
-	w.browser.Resize()
+	w.resize()
+	if restored.Fullscreen {
+		w.SetFullscreen(true)
+	}
 
-	w.appRegion()
+	w.bridge.setup()
+	w.browser.Init(w.frame.script())
 	return true
 }
 
+// closeDestroy 是 closeWithResult 发送的 WM_CLOSE 的 wParam，表示关闭时销毁窗口而不是隐藏
+const closeDestroy = 1
+
+// closeWithResult 记录页面传入的值并通过 WM_CLOSE 关闭窗口，OnCloseRequested 的监听可以取消关闭，
+// 不受 HideWindowOnClose 影响
+func (w *webview) closeWithResult(result json.RawMessage) {
+	w.result.set(result)
+	_, _, _ = w32.User32PostMessageW.Call(w.hwnd, w32.WMClose, closeDestroy, 0)
+}
+
+// enableParent 在模态窗口关闭时重新启用 Parent，需要在销毁窗口之前调用，否则 Parent 不会被激活
+func (w *webview) enableParent() {
+	if w.options.Modal && w.options.Parent != nil {
+		w.options.Parent.setEnabled(true)
+	}
+}
+
+// onThread 返回当前 goroutine 是否在窗口的 UI 线程中执行
+func (w *webview) onThread() bool {
+	thread, _, _ := w32.User32GetWindowThreadProcessId.Call(w.hwnd, 0)
+	current, _, _ := w32.Kernel32GetCurrentThreadID.Call()
+	return thread == current
+}
+
 func (w *webview) Destroy() {
 	_, _, _ = w32.User32PostMessageW.Call(w.hwnd, w32.WMClose, 0, 0)
 }
@@ -534,12 +684,183 @@ func (w *webview) SetSize(width int, height int, hints Hint) {
 		_, _, _ = w32.User32SetWindowPos.Call(
 			w.hwnd, 0, uintptr(r.Left), uintptr(r.Top), uintptr(r.Right-r.Left), uintptr(r.Bottom-r.Top),
 			w32.SWPNoZOrder|w32.SWPNoActivate|w32.SWPNoMove|w32.SWPFrameChanged)
-		w.browser.Resize()
+		w.resize()
+	}
+}
+
+// trackNormalRect 在窗口移动或者改变大小后记录普通状态下的位置和大小，用于保存窗口状态
+func (w *webview) trackNormalRect() {
+	if w.fullscreen {
+		return
+	}
+	if r, _, _ := w32.User32IsIconic.Call(w.hwnd); r != 0 {
+		return
+	}
+	if w.IsMaximized() {
+		return
+	}
+	_, _, _ = w32.User32GetWindowRect.Call(w.hwnd, uintptr(unsafe.Pointer(&w.normalRect)))
+}
+
+// sizeChanged 在 WM_SIZE 时触发 OnResize、OnMinimize 和 OnRestore，lp 为内容区域的宽高
+func (w *webview) sizeChanged(wp, lp uintptr) {
+	iconic := wp == w32.SizeMinimized
+	if iconic != w.iconic {
+		w.iconic = iconic
+		if iconic {
+			w.minimized()
+		} else {
+			w.restored()
+		}
+	}
+	if !iconic {
+		w.resized(int(lp&0xffff), int((lp>>16)&0xffff))
+	}
+}
+
+// saveState 在窗口销毁前保存窗口状态
+func (w *webview) saveState() {
+	if w.state == nil {
+		return
+	}
+	r := w.normalRect
+	if w.fullscreen {
+		r = w.savedRect
+	}
+	w.state.save(WindowState{
+		Bounds:     rectBounds(r),
+		Maximized:  w.IsMaximized(),
+		Fullscreen: w.fullscreen,
+		Monitor:    rectBounds(w.monitorInfo().RcWork),
+	}, w.logger)
+}
+
+func rectBounds(r w32.Rect) Bounds {
+	return Bounds{X: int(r.Left), Y: int(r.Top), Width: int(r.Right - r.Left), Height: int(r.Bottom - r.Top)}
+}
+
+var (
+	monitorsMu   sync.Mutex
+	monitorList  []Bounds
+	enumMonitors = windows.NewCallback(func(monitor, hdc, rect, data uintptr) uintptr {
+		mi := w32.MonitorInfo{CbSize: uint32(unsafe.Sizeof(w32.MonitorInfo{}))}
+		_, _, _ = w32.User32GetMonitorInfoW.Call(monitor, uintptr(unsafe.Pointer(&mi)))
+		if mi.DwFlags&w32.MonitorInfoFPrimary != 0 {
+			monitorList = append([]Bounds{rectBounds(mi.RcWork)}, monitorList...)
+		} else {
+			monitorList = append(monitorList, rectBounds(mi.RcWork))
+		}
+		return 1
+	})
+)
+
+// monitors 返回所有显示器的工作区域，第一个为主显示器
+func monitors() []Bounds {
+	monitorsMu.Lock()
+	defer monitorsMu.Unlock()
+	monitorList = nil
+	_, _, _ = w32.User32EnumDisplayMonitors.Call(0, 0, enumMonitors, 0)
+	return append([]Bounds{}, monitorList...)
+}
+
+// SetAlwaysOnTop 设置窗口是否总是显示在其他窗口上面
+func (w *webview) SetAlwaysOnTop(onTop bool) {
+	after := w32.HWNDNoTopMost
+	if onTop {
+		after = w32.HWNDTopMost
+	}
+	_, _, _ = w32.User32SetWindowPos.Call(w.hwnd, after, 0, 0, 0, 0, w32.SWPNoMove|w32.SWPNoSize|w32.SWPNoActivate)
+}
+
+// SetPosition 把窗口移动到屏幕坐标 x, y
+func (w *webview) SetPosition(x, y int) {
+	_, _, _ = w32.User32SetWindowPos.Call(
+		w.hwnd, 0, uintptr(x), uintptr(y), 0, 0,
+		w32.SWPNoSize|w32.SWPNoZOrder|w32.SWPNoActivate)
+}
+
+// GetPosition 返回窗口左上角的屏幕坐标，GetWindowRect 可以在任意线程调用
+func (w *webview) GetPosition() (x, y int) {
+	var r w32.Rect
+	_, _, _ = w32.User32GetWindowRect.Call(w.hwnd, uintptr(unsafe.Pointer(&r)))
+	return int(r.Left), int(r.Top)
+}
+
+// GetSize 返回窗口内容区域的大小，和 SetSize 的 width、height 对应
+func (w *webview) GetSize() (width, height int) {
+	var r w32.Rect
+	_, _, _ = w32.User32GetClientRect.Call(w.hwnd, uintptr(unsafe.Pointer(&r)))
+	return int(r.Right - r.Left), int(r.Bottom - r.Top)
+}
+
+func (w *webview) Minimize() {
+	_, _, _ = w32.User32ShowWindow.Call(w.hwnd, w32.SWMinimize)
+}
+
+func (w *webview) Maximize() {
+	_, _, _ = w32.User32ShowWindow.Call(w.hwnd, w32.SWMaximize)
+}
+
+// Restore 把最小化或者最大化的窗口恢复为普通状态
+func (w *webview) Restore() {
+	_, _, _ = w32.User32ShowWindow.Call(w.hwnd, w32.SWRestore)
+}
+
+func (w *webview) IsMaximized() bool {
+	r, _, _ := w32.User32IsZoomed.Call(w.hwnd)
+	return r != 0
+}
+
+// monitorInfo 返回窗口所在的显示器的信息
+func (w *webview) monitorInfo() w32.MonitorInfo {
+	mi := w32.MonitorInfo{CbSize: uint32(unsafe.Sizeof(w32.MonitorInfo{}))}
+	monitor, _, _ := w32.User32MonitorFromWindow.Call(w.hwnd, w32.MonitorDefaultToNearest)
+	_, _, _ = w32.User32GetMonitorInfoW.Call(monitor, uintptr(unsafe.Pointer(&mi)))
+	return mi
+}
+
+// SetFullscreen 全屏显示窗口或者退出全屏，全屏时去掉边框并覆盖窗口所在的整个显示器，
+// 退出全屏时恢复之前的样式和位置
+func (w *webview) SetFullscreen(fullscreen bool) {
+	if fullscreen == w.fullscreen {
+		return
+	}
+	index := w32.GWLStyle
+	if fullscreen {
+		w.savedStyle, _, _ = w32.User32GetWindowLongPtrW.Call(w.hwnd, uintptr(index))
+		_, _, _ = w32.User32GetWindowRect.Call(w.hwnd, uintptr(unsafe.Pointer(&w.savedRect)))
+		r := w.monitorInfo().RcMonitor
+		_, _, _ = w32.User32SetWindowLongPtrW.Call(w.hwnd, uintptr(index), w.savedStyle&^w32.WSOverlappedWindow|w32.WSPopup)
+		_, _, _ = w32.User32SetWindowPos.Call(
+			w.hwnd, 0, uintptr(r.Left), uintptr(r.Top), uintptr(r.Right-r.Left), uintptr(r.Bottom-r.Top),
+			w32.SWPNoZOrder|w32.SWPFrameChanged)
+	} else {
+		r := w.savedRect
+		_, _, _ = w32.User32SetWindowLongPtrW.Call(w.hwnd, uintptr(index), w.savedStyle)
+		_, _, _ = w32.User32SetWindowPos.Call(
+			w.hwnd, 0, uintptr(r.Left), uintptr(r.Top), uintptr(r.Right-r.Left), uintptr(r.Bottom-r.Top),
+			w32.SWPNoZOrder|w32.SWPFrameChanged)
 	}
+	w.fullscreen = fullscreen
+	w.resize()
+}
+
+// Center 把窗口移动到所在显示器工作区域（不包括任务栏）的中间
+func (w *webview) Center() {
+	var r w32.Rect
+	_, _, _ = w32.User32GetWindowRect.Call(w.hwnd, uintptr(unsafe.Pointer(&r)))
+	work := w.monitorInfo().RcWork
+	x := work.Left + (work.Right-work.Left-(r.Right-r.Left))/2
+	y := work.Top + (work.Bottom-work.Top-(r.Bottom-r.Top))/2
+	w.SetPosition(int(x), int(y))
 }
 
-func (w *webview) Init(js string) {
-	w.browser.Init(js)
+func (w *webview) Init(js string) ScriptID {
+	return w.bridge.init(js)
+}
+
+func (w *webview) RemoveInit(id ScriptID) {
+	w.bridge.removeScript(id)
 }
 
 func (w *webview) Eval(js string) {
@@ -550,41 +871,19 @@ func (w *webview) Dispatch(f func()) {
 	w.m.Lock()
 	w.dispatchq = append(w.dispatchq, f)
 	w.m.Unlock()
-	_, _, _ = w32.User32PostThreadMessageW.Call(w.mainthread, w32.WMApp, 0, 0)
+	_, _, _ = w32.User32PostMessageW.Call(w.hwnd, w32.WMApp, 0, 0)
 }
 
-func (w *webview) Bind(name string, f interface{}) error {
-	v := reflect.ValueOf(f)
-	if v.Kind() != reflect.Func {
-		return errors.New("only functions can be bound")
-	}
-	if n := v.Type().NumOut(); n > 2 {
-		return errors.New("function may only return a value or a value+error")
-	}
-	w.m.Lock()
-	w.bindings[name] = f
-	w.m.Unlock()
+func (w *webview) Bind(name string, f interface{}, opts ...BindOption) error {
+	return w.bridge.bind(name, f, opts...)
+}
 
-	w.Init("(function() { var name = " + jsString(name) + ";" + `
-		var RPC = window._rpc = (window._rpc || {nextSeq: 1});
-		window[name] = function() {
-		  var seq = RPC.nextSeq++;
-		  var promise = new Promise(function(resolve, reject) {
-			RPC[seq] = {
-			  resolve: resolve,
-			  reject: reject,
-			};
-		  });
-		  window.external.invoke(JSON.stringify({
-			id: seq,
-			method: name,
-			params: Array.prototype.slice.call(arguments),
-		  }));
-		  return promise;
-		}
-	})()`)
+func (w *webview) Unbind(name string) {
+	w.bridge.unbind(name)
+}
 
-	return nil
+func (w *webview) BindObject(ns string, obj interface{}, opts ...BindOption) error {
+	return w.bridge.bindObject(ns, obj, opts...)
 }
 
 func (w *webview) Hide() {
@@ -596,19 +895,345 @@ func (w *webview) Show() {
 	w32.User32SwitchToThisWindow.Call(w.hwnd, uintptr(1))
 }
 
+// listenNavigation 把 WebView2 的跳转相关事件转发给 OnNavigationStarting 等注册的监听
+func (w *webview) listenNavigation(chromium *edge.Chromium) {
+	chromium.OnNavigationStarting(func(sender *edge.ICoreWebView2, args *edge.ICoreWebView2NavigationStartingEventArgs) {
+		e := &NavigationStartingEvent{}
+		e.URL, _ = args.GetUri()
+		e.NavigationID, _ = args.GetNavigationId()
+		e.IsUserInitiated, _ = args.GetIsUserInitiated()
+		e.IsRedirected, _ = args.GetIsRedirected()
+		if w.navigationStarting(e) {
+			_ = args.PutCancel(true)
+		}
+	})
+	chromium.OnNavigationCompleted(func(sender *edge.ICoreWebView2, args *edge.ICoreWebView2NavigationCompletedEventArgs) {
+		e := &NavigationCompletedEvent{Success: args.IsSuccess()}
+		e.NavigationID, _ = args.GetNavigationId()
+		if !e.Success {
+			status, _ := args.GetWebErrorStatus()
+			e.WebErrorStatus = WebErrorStatus(status)
+		}
+		if args2 := args.GetICoreWebView2NavigationCompletedEventArgs2(); args2 != nil {
+			e.HTTPStatus, _ = args2.GetHttpStatusCode()
+			args2.Release()
+		}
+		w.navigationCompleted(e)
+	})
+	chromium.OnSourceChanged(func(sender *edge.ICoreWebView2, args *edge.ICoreWebView2SourceChangedEventArgs) {
+		e := &SourceChangedEvent{}
+		e.URL, _ = sender.GetSource()
+		e.IsNewDocument, _ = args.GetIsNewDocument()
+		w.bridge.setPage(e.URL)
+		w.sourceChanged(e)
+	})
+	chromium.OnDOMContentLoaded(func(sender *edge.ICoreWebView2, args *edge.ICoreWebView2DOMContentLoadedEventArgs) {
+		e := &DOMContentLoadedEvent{}
+		e.NavigationID, _ = args.GetNavigationId()
+		w.domContentLoaded(e)
+	})
+	chromium.OnDocumentTitleChanged(func(sender *edge.ICoreWebView2) {
+		title, _ := sender.GetDocumentTitle()
+		w.titleChanged(title)
+	})
+}
+
+// newWindowRequested 按 NavigationPolicy.NewWindow 处理 window.open 和 target=_blank
+func (w *webview) newWindowRequested(sender *edge.ICoreWebView2, args *edge.ICoreWebView2NewWindowRequestedEventArgs) {
+	uri, err := args.GetUri()
+	if err != nil {
+		return
+	}
+	_ = args.PutHandled(true)
+	switch w.policy.newWindowAction(uri) {
+	case NewWindowCurrent:
+		w.Navigate(uri)
+	case NewWindowDesktop:
+		opts := popupOptions(w.options, uri)
+		if w.openWindow != nil {
+			w.openWindow(opts)
+			return
+		}
+		// 新窗口需要在自己的线程中创建和运行消息循环
+		go func() {
+			win, err := NewWinE(opts, nil)
+			if err != nil {
+				w.logger.Info("open "+uri+" in new window failed:", err)
+				return
+			}
+			win.Run()
+		}()
+	default:
+		w.openExternal(uri)
+	}
+}
+
+// openExternal 在系统默认浏览器中打开 uri，NavigationPolicy.ExternalSchemes 以外的协议只记录日志
+func (w *webview) openExternal(uri string) {
+	if !w.policy.external(uri) {
+		w.logger.Info("navigation to " + uri + " is blocked by navigation policy")
+		return
+	}
+	verb, _ := windows.UTF16PtrFromString("open")
+	file, err := windows.UTF16PtrFromString(uri)
+	if err != nil {
+		return
+	}
+	if err := windows.ShellExecute(windows.Handle(w.hwnd), verb, file, nil, nil, windows.SW_SHOWNORMAL); err != nil {
+		w.logger.Info("open "+uri+" in browser failed:", err)
+	}
+}
+
 func (w *webview) onNavigationCompleted(h func(args *navigationCompletedArg)) {
 	w.browser.(*edge.Chromium).OnNavigationCompleted(func(sender *edge.ICoreWebView2, args *edge.ICoreWebView2NavigationCompletedEventArgs) {
 		h(&navigationCompletedArg{Success: args.IsSuccess()})
 	})
 }
 
-func (w *webview) SetFallbackPage(html string) error {
-	chromium := w.browser.(*edge.Chromium)
-	chromium.PutIsBuiltInErrorPageEnabled(false)
-	w.onNavigationCompleted(func(args *navigationCompletedArg) {
-		if !args.Success {
-			w.SetHtml(html)
+// Handle 拦截页面中地址匹配 pattern 的请求，交给 handler 处理，
+// pattern 中 * 匹配任意多个字符，? 匹配一个字符
+func (w *webview) Handle(pattern string, handler http.Handler) {
+	if w.resources.handle(pattern, handler) {
+		w.browser.(*edge.Chromium).AddWebResourceRequestedFilter(pattern, edge.COREWEBVIEW2_WEB_RESOURCE_CONTEXT_ALL)
+	}
+}
+
+// Use 注册拦截请求的中间件，注册后页面中的所有请求都会经过中间件
+func (w *webview) Use(mw ResourceMiddleware) {
+	if w.resources.use(mw) {
+		w.browser.(*edge.Chromium).AddWebResourceRequestedFilter("*", edge.COREWEBVIEW2_WEB_RESOURCE_CONTEXT_ALL)
+	}
+}
+
+// CacheStats 返回离线缓存的统计信息，没有开启离线缓存时返回零值
+func (w *webview) CacheStats() CacheStats {
+	if w.cache == nil {
+		return CacheStats{}
+	}
+	return w.cache.Stats()
+}
+
+// ClearCache 删除离线缓存中的所有响应
+func (w *webview) ClearCache() error {
+	if w.cache == nil {
+		return nil
+	}
+	return w.cache.Clear()
+}
+
+// AddVirtualHost 把 https://host/ 映射到本地目录 vh.Folder，需要 WebView2 Runtime 支持 ICoreWebView2_3
+func (w *webview) AddVirtualHost(host string, vh VirtualHost) error {
+	folder, err := virtualHostFolder(host, vh)
+	if err != nil {
+		return err
+	}
+	wv3 := w.browser.(*edge.Chromium).GetICoreWebView2_3()
+	if wv3 == nil {
+		return errors.New("virtual host is not supported by the installed WebView2 Runtime")
+	}
+	return wv3.SetVirtualHostNameToFolderMapping(host, folder, edge.COREWEBVIEW2_HOST_RESOURCE_ACCESS_KIND(vh.Access))
+}
+
+// RemoveVirtualHost 移除 AddVirtualHost 映射的目录
+func (w *webview) RemoveVirtualHost(host string) error {
+	wv3 := w.browser.(*edge.Chromium).GetICoreWebView2_3()
+	if wv3 == nil {
+		return errors.New("virtual host is not supported by the installed WebView2 Runtime")
+	}
+	return wv3.ClearVirtualHostNameToFolderMapping(host)
+}
+
+// serveResource 处理拦截到的请求，中间件和 handler 在新的 goroutine 中执行，
+// 执行完后回到 UI 线程创建响应，放行的请求会带上中间件修改后的请求头
+func (w *webview) serveResource(req *edge.ICoreWebView2WebResourceRequest, args *edge.ICoreWebView2WebResourceRequestedEventArgs) {
+	uri, err := req.GetUri()
+	if err != nil {
+		return
+	}
+	if !w.resources.intercepts(uri) {
+		return
+	}
+	rr, err := readResourceRequest(uri, req)
+	if err != nil {
+		w.logger.Info("read resource request failed:", err)
+		return
+	}
+	r, err := rr.httpRequest(w.bridge.ctx)
+	if err != nil {
+		w.logger.Info("invalid resource request:", err)
+		return
+	}
+	ctx, err := args.GetResourceContext()
+	if err != nil {
+		ctx = edge.COREWEBVIEW2_WEB_RESOURCE_CONTEXT_OTHER
+	}
+	deferral, err := args.GetDeferral()
+	if err != nil {
+		w.logger.Info("get resource request deferral failed:", err)
+		return
+	}
+	args.AddRef()
+	req.AddRef()
+	go func() {
+		res := w.resources.serve(&ResourceRequest{Request: r, Context: ResourceContext(ctx)})
+		w.Dispatch(func() {
+			defer func() {
+				_ = deferral.Complete()
+				deferral.Release()
+				req.Release()
+				args.Release()
+			}()
+			if res == nil {
+				if err := applyRequestHeaders(req, rr.Header, r.Header); err != nil {
+					w.logger.Info("modify resource request headers failed:", err)
+				}
+				return
+			}
+			chromium := w.browser.(*edge.Chromium)
+			resp, err := chromium.Environment().CreateWebResourceResponse(res.body.Bytes(), res.status, res.reason(), res.headerString())
+			if err != nil {
+				w.logger.Info("create resource response failed:", err)
+				return
+			}
+			_ = args.PutResponse(resp)
+		})
+	}()
+}
+
+// applyRequestHeaders 把中间件对请求头的修改应用到 WebView2 的请求，需要在 UI 线程调用
+func applyRequestHeaders(req *edge.ICoreWebView2WebResourceRequest, orig [][2]string, header http.Header) error {
+	set, del := headerChanges(orig, header)
+	if len(set) == 0 && len(del) == 0 {
+		return nil
+	}
+	headers, err := req.GetHeaders()
+	if err != nil {
+		return err
+	}
+	defer headers.Release()
+	for _, name := range del {
+		if err := headers.RemoveHeader(name); err != nil {
+			return err
 		}
-	})
+	}
+	for _, h := range set {
+		if err := headers.SetHeader(h[0], h[1]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// cacheRecoverable 返回失败的跳转是否会由离线缓存重新打开
+func (w *webview) cacheRecoverable(e *NavigationCompletedEvent) bool {
+	return w.cache != nil && w.cache.recoverable(e)
+}
+
+// responseReceived 在浏览器收到网络响应后把匹配离线缓存规则的响应保存下来
+func (w *webview) responseReceived(_ *edge.ICoreWebView2, args *edge.ICoreWebView2WebResourceResponseReceivedEventArgs) {
+	req, err := args.GetRequest()
+	if err != nil {
+		return
+	}
+	uri, err := req.GetUri()
+	if err != nil {
+		req.Release()
+		return
+	}
+	method, err := req.GetMethod()
+	req.Release()
+	if err != nil {
+		return
+	}
+	res, err := args.GetResponse()
+	if err != nil {
+		return
+	}
+	defer res.Release()
+	status, err := res.GetStatusCode()
+	if err != nil {
+		return
+	}
+	headers, err := res.GetHeaders()
+	if err != nil {
+		return
+	}
+	pairs, err := headers.All()
+	headers.Release()
+	if err != nil {
+		return
+	}
+	header := http.Header{}
+	for _, kv := range pairs {
+		header.Add(kv[0], kv[1])
+	}
+	if !w.cache.received(method, uri, status, header) {
+		return
+	}
+	err = res.GetContent(func(content *edge.IStream, err error) {
+		if err != nil || content == nil {
+			return
+		}
+		if body, err := io.ReadAll(content); err == nil {
+			w.cache.store(uri, status, header, body)
+		}
+	})
+	if err != nil {
+		w.logger.Info("read response content failed:", err)
+	}
+}
+
+// readResourceRequest 读取 WebView2 请求的方法、请求头和请求体，需要在 UI 线程调用。
+// 读取后请求体会回到开头，放行的请求由 WebView2 继续发送时请求体不变
+func readResourceRequest(uri string, req *edge.ICoreWebView2WebResourceRequest) (*rawRequest, error) {
+	method, err := req.GetMethod()
+	if err != nil {
+		return nil, err
+	}
+	rr := &rawRequest{Method: method, URI: uri}
+	headers, err := req.GetHeaders()
+	if err != nil {
+		return nil, err
+	}
+	rr.Header, err = headers.All()
+	headers.Release()
+	if err != nil {
+		return nil, err
+	}
+	content, err := req.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	if content != nil {
+		rr.Body, err = io.ReadAll(content)
+		if err == nil {
+			_, err = content.Seek(0, io.SeekStart)
+		}
+		content.Release()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rr, nil
+}
+
+// SetFallbackPage 设置页面跳转失败时显示的错误页面，html 为 html/template 模板，数据为 FallbackData
+func (w *webview) SetFallbackPage(html string) error {
+	return w.SetFallback(FallbackOptions{Page: html})
+}
+
+// SetFallback 设置页面跳转失败时显示的错误页面和自动重试，第一次设置时关闭 WebView2 自带的错误页面
+func (w *webview) SetFallback(opts FallbackOptions) error {
+	if w.fallback == nil {
+		fb := &fallback{show: w.SetHtml, navigate: w.Navigate, dispatch: w.Dispatch, recoverable: w.cacheRecoverable}
+		if err := fb.set(opts); err != nil {
+			return err
+		}
+		w.browser.(*edge.Chromium).PutIsBuiltInErrorPageEnabled(false)
+		w.fallback = fb
+		w.bridge.retry = fb.retry
+		fb.listen(&w.navigationEvents)
+		return nil
+	}
+	return w.fallback.set(opts)
+}
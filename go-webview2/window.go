@@ -1,7 +1,15 @@
+//go:build windows
+// +build windows
+
 package webview2
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
 	"runtime"
+	"strings"
 	"sync"
 	"unsafe"
 
@@ -24,8 +32,24 @@ const (
 	eventBind
 	eventHide
 	eventShow
+	eventRemoveInit
+	eventUnbind
+	eventHandle
+	eventAddVirtualHost
+	eventRemoveVirtualHost
+	eventUse
+	eventSetPosition
+	eventMinimize
+	eventMaximize
+	eventRestore
+	eventSetFullscreen
+	eventCenter
+	eventSetAlwaysOnTop
 )
 
+// wmWindowEvent 是 Window 发给窗口的操作的消息，wParam 为 *winEvent
+const wmWindowEvent = 10086
+
 type winEvent struct {
 	name eventName
 	data any
@@ -37,9 +61,30 @@ type setSizeParam struct {
 	hint   Hint
 }
 
+type positionParam struct {
+	x int
+	y int
+}
+
+type initParam struct {
+	id ScriptID
+	js string
+}
+
+type handleParam struct {
+	pattern string
+	handler http.Handler
+}
+
+type virtualHostParam struct {
+	host string
+	vh   VirtualHost
+}
+
 type bindParam struct {
 	name string
 	fn   any
+	opts []BindOption
 }
 
 type Window struct {
@@ -47,80 +92,82 @@ type Window struct {
 	ready    bool
 	readyMu  sync.Mutex
 	preReady []func()
-	hasTray  bool
+
+	// bound 是通过 Bind 绑定的函数，Bind 在 webview 准备好之后才生效，这里提前记录
+	bound   map[string]any
+	boundMu sync.Mutex
 }
 
+// NewWin 在当前线程中创建窗口，创建失败时返回 nil，需要失败原因时使用 NewWinE
 func NewWin(option WebViewOptions, trayOpt *tray.Tray) *Window {
+	win, _ := NewWinE(option, trayOpt)
+	return win
+}
+
+// NewWinE 和 NewWin 一样，创建失败时返回原因，当前 goroutine 会固定在当前线程，Run 必须在同一个 goroutine 中执行
+func NewWinE(option WebViewOptions, trayOpt *tray.Tray) (*Window, error) {
 	runtime.LockOSThread()
+	win, err := newWindow(option, trayOpt)
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, err
+	}
+	return win, nil
+}
+
+// newWindow 在当前线程中创建窗口，Window 的操作通过窗口消息在当前线程中执行
+func newWindow(option WebViewOptions, trayOpt *tray.Tray) (*Window, error) {
+	wv, err := newWebview(option)
+	if err != nil {
+		return nil, err
+	}
 	win := &Window{
-		hasTray: trayOpt != nil,
+		webview: wv,
+		bound:   map[string]any{},
+	}
+	if trayOpt != nil {
+		// 托盘在窗口真正销毁后才退出，OnCloseRequested 取消关闭或者隐藏窗口时托盘仍然保留
+		wv.destroyed = func() {
+			tray.Quit()
+			wv.Terminate()
+		}
 	}
-	win.webview = NewWithOptions(option).(*webview)
+	// 发给页面的消息要等到 webview 准备好之后才能发送
+	win.webview.bridge.dispatch = win.Dispatch
+	win.webview.events = win.handleEvent
+	win.preRun()
 
-	return win
+	return win, nil
 }
 
-// 启动事件循环
+// 启动事件循环，通过 App 创建的窗口由 App.Run 运行事件循环，不需要调用
 func (w *Window) Run() {
-	w.preRun()
+	messageLoop(nil)
+	runtime.UnlockOSThread()
+}
+
+// messageLoop 运行当前线程的消息循环，直到收到 WM_QUIT 或者 done 被关闭，
+// done 不为 nil 时是嵌套的消息循环，收到的 WM_QUIT 会重新发送给外层的消息循环。
+// 每处理完一个消息都会检查 done，窗口销毁时发送的 WM_NULL 保证关闭后不会一直阻塞在 GetMessageW
+func messageLoop(done <-chan struct{}) {
 	var msg w32.Msg
 	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
 		_, _, _ = w32.User32GetMessageW.Call(
 			uintptr(unsafe.Pointer(&msg)),
 			0,
 			0,
 			0,
 		)
-		if msg.Message == w32.WMApp {
-			w.webview.m.Lock()
-			q := append([]func(){}, w.webview.dispatchq...)
-			w.webview.dispatchq = []func(){}
-			w.webview.m.Unlock()
-			for _, v := range q {
-				v()
+		if msg.Message == w32.WMQuit {
+			if done != nil {
+				_, _, _ = w32.User32PostQuitMessage.Call(msg.WParam)
 			}
-		} else if msg.Message == w32.WMQuit {
 			break
-		} else if msg.Message == 10086 {
-			event := (*winEvent)(unsafe.Pointer(msg.WParam))
-			switch event.name {
-			case eventInit:
-				js := event.data.(string)
-				w.webview.Init(js)
-			case eventTerminate:
-				w.webview.Terminate()
-			case eventDispatch:
-				fn := event.data.(func())
-				w.webview.Dispatch(fn)
-			case eventDestroy:
-				if w.hasTray {
-					tray.Quit()
-				}
-				w.webview.Destroy()
-			case eventSetTitle:
-				title := event.data.(string)
-				w.webview.SetTitle(title)
-			case eventSetSize:
-				size := event.data.(setSizeParam)
-				w.webview.SetSize(size.width, size.height, size.hint)
-			case eventNavigate:
-				u := event.data.(string)
-				w.webview.Navigate(u)
-			case eventSetHtml:
-				html := event.data.(string)
-				w.webview.SetHtml(html)
-			case eventEval:
-				js := event.data.(string)
-				w.webview.Eval(js)
-			case eventBind:
-				b := event.data.(bindParam)
-				w.webview.Bind(b.name, b.fn)
-			case eventHide:
-				w.webview.Hide()
-			case eventShow:
-				w.webview.Show()
-			}
-			continue
 		}
 		r, _, _ := w32.User32GetAncestor.Call(uintptr(msg.Hwnd), w32.GARoot)
 		r, _, _ = w32.User32IsDialogMessage.Call(r, uintptr(unsafe.Pointer(&msg)))
@@ -130,8 +177,80 @@ func (w *Window) Run() {
 		_, _, _ = w32.User32TranslateMessage.Call(uintptr(unsafe.Pointer(&msg)))
 		_, _, _ = w32.User32DispatchMessageW.Call(uintptr(unsafe.Pointer(&msg)))
 	}
+}
 
-	runtime.UnlockOSThread()
+// handleEvent 在 UI 线程中执行 dispatch 发送的操作
+func (w *Window) handleEvent(event *winEvent) {
+	switch event.name {
+	case eventInit:
+		p := event.data.(initParam)
+		w.webview.bridge.addScript(p.id, p.js)
+	case eventRemoveInit:
+		w.webview.RemoveInit(event.data.(ScriptID))
+	case eventUnbind:
+		w.webview.Unbind(event.data.(string))
+	case eventHandle:
+		p := event.data.(handleParam)
+		w.webview.Handle(p.pattern, p.handler)
+	case eventUse:
+		w.webview.Use(event.data.(ResourceMiddleware))
+	case eventAddVirtualHost:
+		p := event.data.(virtualHostParam)
+		if err := w.webview.AddVirtualHost(p.host, p.vh); err != nil {
+			w.webview.logger.Info("add virtual host "+p.host+" failed:", err)
+		}
+	case eventRemoveVirtualHost:
+		host := event.data.(string)
+		if err := w.webview.RemoveVirtualHost(host); err != nil {
+			w.webview.logger.Info("remove virtual host "+host+" failed:", err)
+		}
+	case eventTerminate:
+		w.webview.Terminate()
+	case eventDispatch:
+		fn := event.data.(func())
+		w.webview.Dispatch(fn)
+	case eventDestroy:
+		w.webview.Destroy()
+	case eventSetTitle:
+		title := event.data.(string)
+		w.webview.SetTitle(title)
+	case eventSetSize:
+		size := event.data.(setSizeParam)
+		w.webview.SetSize(size.width, size.height, size.hint)
+	case eventNavigate:
+		u := event.data.(string)
+		w.webview.Navigate(u)
+	case eventSetHtml:
+		html := event.data.(string)
+		w.webview.SetHtml(html)
+	case eventEval:
+		js := event.data.(string)
+		w.webview.Eval(js)
+	case eventBind:
+		b := event.data.(bindParam)
+		if err := w.webview.Bind(b.name, b.fn, b.opts...); err != nil {
+			w.webview.logger.Info("bind "+b.name+" failed:", err)
+		}
+	case eventHide:
+		w.webview.Hide()
+	case eventShow:
+		w.webview.Show()
+	case eventSetPosition:
+		p := event.data.(positionParam)
+		w.webview.SetPosition(p.x, p.y)
+	case eventMinimize:
+		w.webview.Minimize()
+	case eventMaximize:
+		w.webview.Maximize()
+	case eventRestore:
+		w.webview.Restore()
+	case eventSetFullscreen:
+		w.webview.SetFullscreen(event.data.(bool))
+	case eventCenter:
+		w.webview.Center()
+	case eventSetAlwaysOnTop:
+		w.webview.SetAlwaysOnTop(event.data.(bool))
+	}
 }
 
 func (w *Window) onReady() {
@@ -166,14 +285,39 @@ func (w *Window) dispatch(name eventName, data any) {
 		w.readyMu.Unlock()
 		return
 	}
-	w32.User32PostThreadMessageW.Call(
-		w.webview.mainthread,
-		10086,
+	// 发给窗口而不是线程，同一个线程中有多个窗口时也能找到对应的窗口
+	w32.User32PostMessageW.Call(
+		w.webview.hwnd,
+		wmWindowEvent,
 		uintptr(unsafe.Pointer(&winEvent{name, data})),
 		0,
 	)
 }
 
+// ShowModal 显示窗口并等待窗口关闭，返回页面通过 window.desktop.close(result) 传入的值，
+// 用户直接关闭窗口时返回 nil。在窗口的 UI 线程中调用时会执行嵌套的消息循环，
+// 在其他 goroutine（如绑定的 Go 函数）中调用时阻塞当前 goroutine
+func (w *Window) ShowModal() json.RawMessage {
+	w.Show()
+	if w.webview.onThread() {
+		messageLoop(w.webview.result.done)
+	}
+	return w.webview.result.wait()
+}
+
+func (w *Window) nativeHandle() uintptr {
+	return w.webview.hwnd
+}
+
+// setEnabled 启用或者禁用窗口，EnableWindow 可以在任意线程调用
+func (w *Window) setEnabled(enabled bool) {
+	var v uintptr
+	if enabled {
+		v = 1
+	}
+	_, _, _ = w32.User32EnableWindow.Call(w.webview.hwnd, v)
+}
+
 func (w *Window) Terminate() {
 	w.dispatch(eventTerminate, nil)
 }
@@ -193,11 +337,231 @@ func (w *Window) SetSize(width int, height int, hint Hint) {
 	w.dispatch(eventSetSize, setSizeParam{width, height, hint})
 }
 
-func (w *Window) Init(js string) {
-	w.dispatch(eventInit, js)
+// SetMinSize 设置用户调整窗口大小时的最小值
+func (w *Window) SetMinSize(width int, height int) {
+	w.SetSize(width, height, HintMin)
+}
+
+// SetMaxSize 设置用户调整窗口大小时的最大值
+func (w *Window) SetMaxSize(width int, height int) {
+	w.SetSize(width, height, HintMax)
+}
+
+// SetPosition 把窗口移动到屏幕坐标 x, y
+func (w *Window) SetPosition(x int, y int) {
+	w.dispatch(eventSetPosition, positionParam{x, y})
+}
+
+// GetPosition 返回窗口左上角的屏幕坐标
+func (w *Window) GetPosition() (x int, y int) {
+	return w.webview.GetPosition()
+}
+
+// GetSize 返回窗口内容区域的大小
+func (w *Window) GetSize() (width int, height int) {
+	return w.webview.GetSize()
+}
+
+func (w *Window) Minimize() {
+	w.dispatch(eventMinimize, nil)
+}
+
+func (w *Window) Maximize() {
+	w.dispatch(eventMaximize, nil)
+}
+
+// Restore 把最小化或者最大化的窗口恢复为普通状态
+func (w *Window) Restore() {
+	w.dispatch(eventRestore, nil)
+}
+
+func (w *Window) IsMaximized() bool {
+	return w.webview.IsMaximized()
+}
+
+// SetFullscreen 全屏显示窗口或者退出全屏
+func (w *Window) SetFullscreen(fullscreen bool) {
+	w.dispatch(eventSetFullscreen, fullscreen)
+}
+
+// Center 把窗口移动到所在显示器的中间
+func (w *Window) Center() {
+	w.dispatch(eventCenter, nil)
+}
+
+// SetAlwaysOnTop 设置窗口是否总是显示在其他窗口上面
+func (w *Window) SetAlwaysOnTop(onTop bool) {
+	w.dispatch(eventSetAlwaysOnTop, onTop)
+}
+
+// Init 注入在每个页面创建时执行的脚本，返回的 id 可以用于 RemoveInit
+func (w *Window) Init(js string) ScriptID {
+	id := w.webview.bridge.newScriptID()
+	w.dispatch(eventInit, initParam{id, js})
+	return id
+}
+
+// RemoveInit 移除通过 Init 注入的脚本，之后打开的页面不再执行，对当前页面没有影响
+func (w *Window) RemoveInit(id ScriptID) {
+	w.dispatch(eventRemoveInit, id)
+}
+
+// Bind 绑定 Go 函数 f，页面中通过 window[name] 调用，f 不是可以绑定的函数时返回错误
+func (w *Window) Bind(name string, f interface{}, opts ...BindOption) error {
+	if _, err := newBinding(f, opts); err != nil {
+		return err
+	}
+	w.boundMu.Lock()
+	w.bound[name] = f
+	w.boundMu.Unlock()
+	w.dispatch(eventBind, bindParam{name, f, opts})
+	return nil
+}
+
+// BindObject 把 obj 导出的方法绑定到页面的 window[ns] 对象下，
+// 如 BindObject("fs", fs) 后页面中可以调用 window.fs.readFile()
+func (w *Window) BindObject(ns string, obj interface{}, opts ...BindOption) error {
+	methods, err := objectMethods(ns, obj, opts)
+	if err != nil {
+		return err
+	}
+	for name, fn := range methods {
+		if _, err := newBinding(fn, opts); err != nil {
+			return errors.New(name + ": " + err.Error())
+		}
+	}
+	for name, fn := range methods {
+		_ = w.Bind(name, fn, opts...)
+	}
+	return nil
+}
+
+// Unbind 移除绑定的函数，name 为 BindObject 的 ns 时移除该对象下所有的函数，
+// 页面中的 JS 函数会被删除，已经在执行的调用不受影响
+func (w *Window) Unbind(name string) {
+	w.boundMu.Lock()
+	for n := range w.bound {
+		if n == name || strings.HasPrefix(n, name+".") {
+			delete(w.bound, n)
+		}
+	}
+	w.boundMu.Unlock()
+	w.dispatch(eventUnbind, name)
+}
+
+// Handle 拦截页面中地址匹配 pattern 的请求，交给 handler 处理，
+// pattern 中 * 匹配任意多个字符，? 匹配一个字符，如 https://api.example.com/*，
+// handler 在单独的 goroutine 中执行
+func (w *Window) Handle(pattern string, handler http.Handler) {
+	w.dispatch(eventHandle, handleParam{pattern, handler})
+}
+
+// Use 注册拦截请求的中间件，可以按地址和资源类型放行、拦截、重定向请求或者直接返回响应，
+// 先注册的中间件先执行，中间件在单独的 goroutine 中执行
+func (w *Window) Use(mw ResourceMiddleware) {
+	w.dispatch(eventUse, mw)
+}
+
+// OnNavigationStarting 监听页面开始跳转，fn 中可以调用 e.Cancel() 取消跳转，返回取消监听的函数，
+// fn 在 UI 线程中执行，不能阻塞
+func (w *Window) OnNavigationStarting(fn func(e *NavigationStartingEvent)) func() {
+	return w.webview.OnNavigationStarting(fn)
+}
+
+// OnNavigationCompleted 监听页面跳转完成，包括跳转失败，返回取消监听的函数
+func (w *Window) OnNavigationCompleted(fn func(e *NavigationCompletedEvent)) func() {
+	return w.webview.OnNavigationCompleted(fn)
+}
+
+// OnSourceChanged 监听页面地址变化，返回取消监听的函数
+func (w *Window) OnSourceChanged(fn func(e *SourceChangedEvent)) func() {
+	return w.webview.OnSourceChanged(fn)
+}
+
+// OnDOMContentLoaded 监听页面 DOMContentLoaded，返回取消监听的函数
+func (w *Window) OnDOMContentLoaded(fn func(e *DOMContentLoadedEvent)) func() {
+	return w.webview.OnDOMContentLoaded(fn)
+}
+
+// OnTitleChanged 监听页面标题变化，返回取消监听的函数
+func (w *Window) OnTitleChanged(fn func(title string)) func() {
+	return w.webview.OnTitleChanged(fn)
+}
+
+// OnCloseRequested 监听用户关闭窗口，包括调用 Destroy 和页面调用 window.desktop.close，
+// fn 返回 false 时取消关闭，返回取消监听的函数，fn 在 UI 线程中执行
+func (w *Window) OnCloseRequested(fn func() bool) func() {
+	return w.webview.OnCloseRequested(fn)
+}
+
+// OnFocus 监听窗口获得焦点，返回取消监听的函数
+func (w *Window) OnFocus(fn func()) func() {
+	return w.webview.OnFocus(fn)
+}
+
+// OnBlur 监听窗口失去焦点，返回取消监听的函数
+func (w *Window) OnBlur(fn func()) func() {
+	return w.webview.OnBlur(fn)
 }
-func (w *Window) Bind(name string, f interface{}) {
-	w.dispatch(eventBind, bindParam{name, f})
+
+// OnResize 监听窗口内容区域大小变化，返回取消监听的函数
+func (w *Window) OnResize(fn func(width int, height int)) func() {
+	return w.webview.OnResize(fn)
+}
+
+// OnMove 监听窗口移动，返回取消监听的函数
+func (w *Window) OnMove(fn func(x int, y int)) func() {
+	return w.webview.OnMove(fn)
+}
+
+// OnMinimize 监听窗口最小化，返回取消监听的函数
+func (w *Window) OnMinimize(fn func()) func() {
+	return w.webview.OnMinimize(fn)
+}
+
+// OnRestore 监听窗口从最小化恢复，返回取消监听的函数
+func (w *Window) OnRestore(fn func()) func() {
+	return w.webview.OnRestore(fn)
+}
+
+// CacheStats 返回离线缓存的统计信息，没有开启离线缓存时返回零值
+func (w *Window) CacheStats() CacheStats {
+	return w.webview.CacheStats()
+}
+
+// ClearCache 删除离线缓存中的所有响应
+func (w *Window) ClearCache() error {
+	return w.webview.ClearCache()
+}
+
+// AddVirtualHost 把 https://host/ 映射到本地目录 vh.Folder，host 为空或者目录不存在时返回错误，
+// 映射在 UI 线程中完成，WebView2 Runtime 不支持时记录日志
+func (w *Window) AddVirtualHost(host string, vh VirtualHost) error {
+	if _, err := virtualHostFolder(host, vh); err != nil {
+		return err
+	}
+	w.dispatch(eventAddVirtualHost, virtualHostParam{host, vh})
+	return nil
+}
+
+// RemoveVirtualHost 移除 AddVirtualHost 映射的目录，host 为空时返回错误
+func (w *Window) RemoveVirtualHost(host string) error {
+	if host == "" {
+		return errors.New("virtual host name is empty")
+	}
+	w.dispatch(eventRemoveVirtualHost, host)
+	return nil
+}
+
+// Bindings 返回通过 Bind 绑定的 Go 函数，key 为页面中的函数名
+func (w *Window) Bindings() map[string]interface{} {
+	w.boundMu.Lock()
+	defer w.boundMu.Unlock()
+	m := make(map[string]interface{}, len(w.bound))
+	for name, f := range w.bound {
+		m[name] = f
+	}
+	return m
 }
 
 func (w *Window) Navigate(url string) {
@@ -219,3 +583,38 @@ func (w *Window) Show() {
 func (w *Window) Hide() {
 	w.dispatch(eventHide, nil)
 }
+
+// EvalAsync 在页面执行 js 并返回表达式的值，值为 Promise 时返回 resolve 的值，
+// 最多等待 DefaultEvalTimeout
+func (w *Window) EvalAsync(js string) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultEvalTimeout)
+	defer cancel()
+	return w.EvalWithContext(ctx, js)
+}
+
+// EvalWithContext 和 EvalAsync 一样，但是由 ctx 控制超时和取消
+func (w *Window) EvalWithContext(ctx context.Context, js string) (json.RawMessage, error) {
+	return w.webview.bridge.evalAsync(ctx, js)
+}
+
+// CallJS 调用页面的函数 fn 并返回结果，fn 支持 a.b.c 形式的路径，
+// 函数返回 Promise 时等待 resolve 后返回
+func (w *Window) CallJS(ctx context.Context, fn string, args ...interface{}) (json.RawMessage, error) {
+	return w.webview.bridge.callJS(ctx, fn, args...)
+}
+
+// Emit 向页面发送事件，页面中通过 window.desktop.on(event, cb) 监听
+func (w *Window) Emit(event string, payload interface{}) error {
+	return w.webview.bridge.emit(event, payload)
+}
+
+// On 监听页面通过 window.desktop.emit 发送的事件，返回取消监听的函数。
+// event 以 . 分隔，* 匹配任意一段，** 匹配任意多段
+func (w *Window) On(event string, fn func(payload json.RawMessage)) func() {
+	return w.webview.bridge.on(event, fn, false)
+}
+
+// Once 和 On 一样，但是只会触发一次
+func (w *Window) Once(event string, fn func(payload json.RawMessage)) func() {
+	return w.webview.bridge.on(event, fn, true)
+}
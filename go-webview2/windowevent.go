@@ -0,0 +1,176 @@
+package webview2
+
+import "sync"
+
+// windowRuntime 把窗口事件转发为 window.desktop 上的 DOM 事件，依赖 rpcRuntime，
+// 页面中通过 window.desktop.addEventListener("resize", e => e.detail.width) 监听，
+// 事件有 focus、blur、resize、move、minimize、restore
+const windowRuntime = `(function() {
+	var desktop = window.desktop = window.desktop || {};
+	if (desktop.addEventListener) return;
+	var target = new EventTarget();
+	desktop.addEventListener = target.addEventListener.bind(target);
+	desktop.removeEventListener = target.removeEventListener.bind(target);
+	desktop.dispatchEvent = target.dispatchEvent.bind(target);
+	window._rpc.handlers["$window"] = function(name, detail) {
+		target.dispatchEvent(new CustomEvent(name, { detail: detail }));
+	};
+})()`
+
+// windowSize 是页面中 resize 事件的 detail
+type windowSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// windowPosition 是页面中 move 事件的 detail
+type windowPosition struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// windowListener 是通过 OnFocus 等注册的监听，fn 为对应事件的函数
+type windowListener struct {
+	fn interface{}
+}
+
+// windowEvents 管理窗口事件的监听，监听函数在 UI 线程中按注册顺序执行，
+// 除了关闭事件以外的事件也会转发给页面
+type windowEvents struct {
+	winm     sync.Mutex
+	closing  []*windowListener
+	focus    []*windowListener
+	blur     []*windowListener
+	resize   []*windowListener
+	move     []*windowListener
+	minimize []*windowListener
+	restore  []*windowListener
+	// send 把事件转发给页面
+	send func(name string, detail interface{})
+}
+
+func (e *windowEvents) add(list *[]*windowListener, fn interface{}) func() {
+	l := &windowListener{fn}
+	e.winm.Lock()
+	*list = append(*list, l)
+	e.winm.Unlock()
+	return func() {
+		e.winm.Lock()
+		defer e.winm.Unlock()
+		for i, v := range *list {
+			if v == l {
+				*list = append((*list)[:i:i], (*list)[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (e *windowEvents) get(list *[]*windowListener) []*windowListener {
+	e.winm.Lock()
+	defer e.winm.Unlock()
+	return append([]*windowListener{}, *list...)
+}
+
+// OnCloseRequested 监听用户关闭窗口，fn 返回 false 时取消关闭，如提示还有没保存的修改，
+// 返回取消监听的函数，fn 在 UI 线程中执行。页面调用 window.desktop.close 时也会触发，App.Quit 关闭窗口时不会触发
+func (e *windowEvents) OnCloseRequested(fn func() bool) func() {
+	return e.add(&e.closing, fn)
+}
+
+// OnFocus 监听窗口获得焦点，返回取消监听的函数，fn 在 UI 线程中执行，不能阻塞
+func (e *windowEvents) OnFocus(fn func()) func() {
+	return e.add(&e.focus, fn)
+}
+
+// OnBlur 监听窗口失去焦点，返回取消监听的函数，fn 在 UI 线程中执行，不能阻塞
+func (e *windowEvents) OnBlur(fn func()) func() {
+	return e.add(&e.blur, fn)
+}
+
+// OnResize 监听窗口大小变化，参数为窗口内容区域的大小，最小化时不会触发，
+// 返回取消监听的函数，fn 在 UI 线程中执行，不能阻塞
+func (e *windowEvents) OnResize(fn func(width int, height int)) func() {
+	return e.add(&e.resize, fn)
+}
+
+// OnMove 监听窗口移动，参数为窗口左上角的屏幕坐标，最小化时不会触发，
+// 返回取消监听的函数，fn 在 UI 线程中执行，不能阻塞
+func (e *windowEvents) OnMove(fn func(x int, y int)) func() {
+	return e.add(&e.move, fn)
+}
+
+// OnMinimize 监听窗口最小化，返回取消监听的函数，fn 在 UI 线程中执行，不能阻塞
+func (e *windowEvents) OnMinimize(fn func()) func() {
+	return e.add(&e.minimize, fn)
+}
+
+// OnRestore 监听窗口从最小化恢复，返回取消监听的函数，fn 在 UI 线程中执行，不能阻塞
+func (e *windowEvents) OnRestore(fn func()) func() {
+	return e.add(&e.restore, fn)
+}
+
+// closeRequested 通知用户关闭窗口，返回是否可以关闭
+func (e *windowEvents) closeRequested() bool {
+	allow := true
+	for _, l := range e.get(&e.closing) {
+		if !l.fn.(func() bool)() {
+			allow = false
+		}
+	}
+	return allow
+}
+
+// notify 执行 list 中没有参数的监听，并把事件转发给页面
+func (e *windowEvents) notify(list *[]*windowListener, name string) {
+	for _, l := range e.get(list) {
+		l.fn.(func())()
+	}
+	e.forward(name, nil)
+}
+
+func (e *windowEvents) focused() {
+	e.notify(&e.focus, "focus")
+}
+
+func (e *windowEvents) blurred() {
+	e.notify(&e.blur, "blur")
+}
+
+func (e *windowEvents) minimized() {
+	e.notify(&e.minimize, "minimize")
+}
+
+func (e *windowEvents) restored() {
+	e.notify(&e.restore, "restore")
+}
+
+func (e *windowEvents) resized(width, height int) {
+	for _, l := range e.get(&e.resize) {
+		l.fn.(func(int, int))(width, height)
+	}
+	e.forward("resize", windowSize{width, height})
+}
+
+func (e *windowEvents) moved(x, y int) {
+	for _, l := range e.get(&e.move) {
+		l.fn.(func(int, int))(x, y)
+	}
+	e.forward("move", windowPosition{x, y})
+}
+
+func (e *windowEvents) forward(name string, detail interface{}) {
+	if e.send != nil {
+		e.send(name, detail)
+	}
+}
+
+// windowEvent 把窗口事件发送给页面，页面中由 windowRuntime 转换为 DOM 事件
+func (b *bridge) windowEvent(name string, detail interface{}) {
+	n, err := NewRPCNotification("$window", name, detail)
+	if err != nil {
+		b.logger.Info("encode window event failed:", err)
+		return
+	}
+	b.send(n)
+}
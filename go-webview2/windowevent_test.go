@@ -0,0 +1,128 @@
+package webview2
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCloseRequestedVeto(t *testing.T) {
+	h := NewHeadless(WebViewOptions{})
+	unsaved := true
+	var calls int
+	off := h.OnCloseRequested(func() bool {
+		calls++
+		return !unsaved
+	})
+	// 其他监听允许关闭时仍然按取消关闭处理，所有监听都会执行
+	h.OnCloseRequested(func() bool {
+		calls++
+		return true
+	})
+
+	if h.RequestClose() {
+		t.Error("RequestClose = true with a veto")
+	}
+	if calls != 2 {
+		t.Errorf("listeners called %d times, want 2", calls)
+	}
+	select {
+	case <-h.done:
+		t.Fatal("window destroyed after a veto")
+	default:
+	}
+
+	off()
+	if !h.RequestClose() {
+		t.Error("RequestClose = false after the veto was removed")
+	}
+	select {
+	case <-h.done:
+	default:
+		t.Error("window was not destroyed")
+	}
+}
+
+func TestCloseRequestedHide(t *testing.T) {
+	h := NewHeadless(WebViewOptions{HideWindowOnClose: true})
+	h.Show()
+	if !h.RequestClose() || h.Visible() {
+		t.Error("HideWindowOnClose did not hide the window")
+	}
+	select {
+	case <-h.done:
+		t.Error("HideWindowOnClose destroyed the window")
+	default:
+	}
+}
+
+// 页面调用 window.desktop.close 时同样可以取消关闭，取消后不保留传入的值
+func TestCloseRequestedModal(t *testing.T) {
+	h := NewHeadless(WebViewOptions{})
+	allow := false
+	h.OnCloseRequested(func() bool { return allow })
+	result := showModal(h)
+
+	h.PostMessage(`{"jsonrpc":"2.0","method":"$close","params":["vetoed"]}`)
+	select {
+	case v := <-result:
+		t.Fatalf("ShowModal returned %s after a veto", v)
+	default:
+	}
+
+	allow = true
+	h.Destroy()
+	if got := modalResult(t, result); got != nil {
+		t.Errorf("ShowModal = %s, want nil", got)
+	}
+}
+
+func TestWindowEvents(t *testing.T) {
+	h := NewHeadless(WebViewOptions{WindowOptions: WindowOptions{Width: 800, Height: 600}})
+	var got []string
+	record := func(s string) { got = append(got, s) }
+	h.OnFocus(func() { record("focus") })
+	h.OnBlur(func() { record("blur") })
+	h.OnResize(func(w, h int) { record("resize " + strconv.Itoa(w) + "x" + strconv.Itoa(h)) })
+	h.OnMove(func(x, y int) { record("move " + strconv.Itoa(x) + "," + strconv.Itoa(y)) })
+	offMinimize := h.OnMinimize(func() { record("minimize") })
+	h.OnRestore(func() { record("restore") })
+	before := len(h.Messages())
+
+	h.Focus()
+	h.Blur()
+	h.SetSize(1024, 768, HintNone)
+	h.SetSize(1024, 768, HintNone)
+	h.SetPosition(10, 20)
+	h.Minimize()
+	h.Minimize()
+	h.Restore()
+	offMinimize()
+	h.Minimize()
+
+	want := []string{"focus", "blur", "resize 1024x768", "move 10,20", "minimize", "restore"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+
+	// 事件同样转发给页面
+	var forwarded []string
+	for _, m := range h.Messages()[before:] {
+		if m.Method != "$window" {
+			continue
+		}
+		params, _ := m.ParamList()
+		var name string
+		_ = json.Unmarshal(params[0], &name)
+		forwarded = append(forwarded, name+" "+string(params[1]))
+	}
+	wantForwarded := []string{
+		"focus null", "blur null", `resize {"width":1024,"height":768}`, `move {"x":10,"y":20}`,
+		"minimize null", "restore null", "minimize null",
+	}
+	if strings.Join(forwarded, ";") != strings.Join(wantForwarded, ";") {
+		t.Errorf("page events = %v, want %v", forwarded, wantForwarded)
+	}
+}
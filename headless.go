@@ -0,0 +1,19 @@
+package desktop
+
+import (
+	"github.com/eyasliu/desktop/go-webview2"
+)
+
+var _ WebView = &webview2.Headless{}
+
+// NewHeadless 新建一个无界面的 webview，不依赖系统 webview，可以在任意系统运行，
+// 所有跳转、注入的 js、执行的 js 都只记录在内存中，
+// 可通过 PostMessage 模拟页面调用 Go 函数，主要用于单元测试
+func NewHeadless(opt *Options) *webview2.Headless {
+	return webview2.NewHeadless(webviewOptions(opt))
+}
+
+// NewHeadlessApp 新建一个管理无界面窗口的 App，可以在任意系统运行，主要用于单元测试
+func NewHeadlessApp(opt *AppOptions) *App {
+	return &App{webview2.NewHeadlessApp(appOptions(opt))}
+}
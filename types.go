@@ -1,12 +1,176 @@
 package desktop
 
 import (
+	"context"
 	_ "embed"
+	"encoding/json"
 	"fmt"
+	"io/fs"
+	"net/http"
 
+	"github.com/eyasliu/desktop/go-webview2"
 	"github.com/eyasliu/desktop/tray"
 )
 
+// BindOption 是 Bind 的可选配置
+type BindOption = webview2.BindOption
+
+// BindTimeout 设置绑定函数的最长执行时间，超时后 js 调用会 reject，函数的 context.Context 参数会被取消
+var BindTimeout = webview2.BindTimeout
+
+// BindOrigins 设置允许调用绑定函数的页面地址，其他页面调用时会被拒绝，格式见 webview2.BindOrigins
+var BindOrigins = webview2.BindOrigins
+
+// AppOrigin 是 Options.Assets 和 Options.Handler 提供页面使用的地址
+const AppOrigin = webview2.AppOrigin
+
+// AssetsHandler 返回提供 assets 中静态文件的 http.Handler，可以在 Options.Handler 中使用
+var AssetsHandler = webview2.AssetsHandler
+
+// ResourceContext 是拦截到的请求的资源类型
+type ResourceContext = webview2.ResourceContext
+
+const (
+	ResourceAll                = webview2.ResourceAll
+	ResourceDocument           = webview2.ResourceDocument
+	ResourceStylesheet         = webview2.ResourceStylesheet
+	ResourceImage              = webview2.ResourceImage
+	ResourceMedia              = webview2.ResourceMedia
+	ResourceFont               = webview2.ResourceFont
+	ResourceScript             = webview2.ResourceScript
+	ResourceXMLHTTPRequest     = webview2.ResourceXMLHTTPRequest
+	ResourceFetch              = webview2.ResourceFetch
+	ResourceTextTrack          = webview2.ResourceTextTrack
+	ResourceEventSource        = webview2.ResourceEventSource
+	ResourceWebSocket          = webview2.ResourceWebSocket
+	ResourceManifest           = webview2.ResourceManifest
+	ResourceSignedExchange     = webview2.ResourceSignedExchange
+	ResourcePing               = webview2.ResourcePing
+	ResourceCSPViolationReport = webview2.ResourceCSPViolationReport
+	ResourceOther              = webview2.ResourceOther
+)
+
+// ResourceRequest 是中间件收到的被拦截的请求
+type ResourceRequest = webview2.ResourceRequest
+
+// Action 是中间件对请求的处理方式
+type Action = webview2.Action
+
+// ResourceHandler 决定如何处理拦截到的请求
+type ResourceHandler = webview2.ResourceHandler
+
+// ResourceMiddleware 是通过 Use 注册的拦截请求的中间件
+type ResourceMiddleware = webview2.ResourceMiddleware
+
+// Recorder 记录经过的请求和处理方式，用于测试
+type Recorder = webview2.Recorder
+
+// RecordedRequest 是 Recorder 记录的一次请求
+type RecordedRequest = webview2.RecordedRequest
+
+var (
+	// Allow 放行请求
+	Allow = webview2.Allow
+	// Block 拦截请求，页面收到 403 响应
+	Block = webview2.Block
+	// Redirect 把请求重定向到指定地址
+	Redirect = webview2.Redirect
+	// Respond 由 http.Handler 返回响应
+	Respond = webview2.Respond
+	// Filter 返回只处理匹配地址和资源类型的请求的中间件
+	Filter = webview2.Filter
+	// NewRecorder 创建一个 Recorder
+	NewRecorder = webview2.NewRecorder
+)
+
+// FallbackOptions 是页面跳转失败时的错误页面和自动重试的配置
+type FallbackOptions = webview2.FallbackOptions
+
+// FallbackData 是错误页面模板的数据
+type FallbackData = webview2.FallbackData
+
+// NavigationPolicy 限制页面可以跳转的地址
+type NavigationPolicy = webview2.NavigationPolicy
+
+// NewWindowAction 是页面打开新窗口时的处理方式
+type NewWindowAction = webview2.NewWindowAction
+
+const (
+	NewWindowCurrent  = webview2.NewWindowCurrent
+	NewWindowDesktop  = webview2.NewWindowDesktop
+	NewWindowExternal = webview2.NewWindowExternal
+)
+
+// NavigationStartingEvent 是页面开始跳转的事件
+type NavigationStartingEvent = webview2.NavigationStartingEvent
+
+// NavigationCompletedEvent 是页面跳转完成的事件
+type NavigationCompletedEvent = webview2.NavigationCompletedEvent
+
+// SourceChangedEvent 是页面地址变化的事件
+type SourceChangedEvent = webview2.SourceChangedEvent
+
+// DOMContentLoadedEvent 是页面 DOMContentLoaded 的事件
+type DOMContentLoadedEvent = webview2.DOMContentLoadedEvent
+
+// WebErrorStatus 是页面跳转失败的原因
+type WebErrorStatus = webview2.WebErrorStatus
+
+// CacheOptions 是离线缓存的配置
+type CacheOptions = webview2.CacheOptions
+
+// CacheRule 是离线缓存的规则
+type CacheRule = webview2.CacheRule
+
+// CacheStats 是离线缓存的统计信息
+type CacheStats = webview2.CacheStats
+
+// VirtualHost 是虚拟主机映射的本地目录和其他来源的访问限制
+type VirtualHost = webview2.VirtualHost
+
+// HostAccess 是虚拟主机中的文件被其他来源的页面访问时的限制
+type HostAccess = webview2.HostAccess
+
+const (
+	HostAccessDeny     = webview2.HostAccessDeny
+	HostAccessAllow    = webview2.HostAccessAllow
+	HostAccessDenyCORS = webview2.HostAccessDenyCORS
+)
+
+// WindowState 是 PersistState 保存的窗口状态
+type WindowState = webview2.WindowState
+
+// Bounds 是屏幕上的矩形区域
+type Bounds = webview2.Bounds
+
+// Hint 是 SetSize 的参数，设置的是窗口大小、固定大小、最小值还是最大值
+type Hint = webview2.Hint
+
+const (
+	HintNone  = webview2.HintNone
+	HintFixed = webview2.HintFixed
+	HintMin   = webview2.HintMin
+	HintMax   = webview2.HintMax
+)
+
+// ScriptID 是 Init 注入的脚本的标识，用于 RemoveInit
+type ScriptID = webview2.ScriptID
+
+// NameCase 是 BindObject 生成的 JS 方法名的命名方式
+type NameCase = webview2.NameCase
+
+const (
+	LowerCamelCase = webview2.LowerCamelCase
+	OriginalCase   = webview2.OriginalCase
+	SnakeCase      = webview2.SnakeCase
+)
+
+// BindCase 设置 BindObject 生成的 JS 方法名的命名方式，默认首字母小写
+var BindCase = webview2.BindCase
+
+// BindExclude 设置 BindObject 不需要绑定的方法，可以是 Go 方法名或者 JS 方法名
+var BindExclude = webview2.BindExclude
+
 //
 type logger interface {
 	Info(v ...interface{})
@@ -31,7 +195,7 @@ type Options struct {
 	Debug bool
 	// 启动webview后访问的url
 	StartURL string
-	// 当webview访问错误时显示的错误页面
+	// 当webview访问错误时显示的错误页面，可以是 html/template 模板，数据为 FallbackData
 	FallbackPage string
 	// webview2 底层使用的用户数据目录
 	DataPath string
@@ -41,22 +205,56 @@ type Options struct {
 	Width int
 	// webview 窗口高度
 	Height int
-	// 刚启动webview时是否隐藏状态，可通过 Show() 方法显示
+	// 刚启动webview时是否隐藏状态，隐藏时页面仍然会正常加载，可通过 Show() 方法立即显示
 	StartHidden bool
 	// 当用户关闭了webview窗口时是否视为隐藏窗口，如果为false，用户关闭窗口后会触发销毁应用
 	HideWindowOnClose bool
-	// webview 窗口是否总是在最顶层
+	// webview 窗口是否总是在最顶层，运行时可通过 SetAlwaysOnTop 修改
 	AlwaysOnTop bool
 	// 系统托盘设置
 	Tray *tray.Tray
 	// 打印日志的实例
 	Logger logger
-	// 是否去掉webview窗口的边框，注意无边框会把右上角最大化最小化等按钮去掉
+	// 是否去掉webview窗口的边框，注意无边框会把右上角最大化最小化等按钮去掉，
+	// 页面中 css 为 -webkit-app-region: drag 的区域作为标题栏，可拖动、双击最大化、拖到屏幕边缘分屏，
+	// 其中 -webkit-app-region: no-drag 的子元素仍然可以点击，data-app-region="maximize" 的元素作为最大化按钮
 	Frameless bool
+	// 无边框窗口边缘可以调整大小的宽度，单位为逻辑像素，为 0 时默认为 6，小于 0 时不能通过边缘调整大小，
+	// 窗口没有最大化时页面会向内缩进这个宽度
+	ResizeBorder int
 	// 打开窗口时是否自动在屏幕中间
 	Center bool
 	// 打开窗口时是否自动聚焦
 	AutoFocus bool
+	// 允许调用绑定函数的页面地址，如 https://app.local、https://*.example.com，
+	// 可通过 BindOrigins 单独设置每个函数，为空时允许所有页面调用
+	AllowedOrigins []string
+	// 页面的静态文件，如打包到程序中的 embed.FS，通过 https://app.local/ 访问，
+	// 没有设置 StartURL 时默认打开 https://app.local/
+	Assets fs.FS
+	// 处理 https://app.local/ 下的请求，和 Assets 同时设置时使用 Handler
+	Handler http.Handler
+	// 把 https 主机名映射到本地目录，key 为主机名，如 app.example 映射到前端的构建目录后，
+	// 页面可以通过 https://app.example/index.html 访问，有稳定的来源，cookie 和 CORS 和普通网站一致
+	VirtualHosts map[string]VirtualHost
+	// 开启离线缓存，匹配规则的 GET 请求的响应缓存到 DataPath 下，
+	// 网络不可用时使用缓存打开页面，没有缓存时才显示 FallbackPage
+	Cache *CacheOptions
+	// 限制页面可以跳转的地址，不允许的跳转会被取消并在系统默认浏览器中打开，
+	// window.open 和 target=_blank 按 NavigationPolicy.NewWindow 处理，为 nil 时不限制
+	NavigationPolicy *NavigationPolicy
+	// 页面跳转失败时显示的错误页面和自动重试，设置后忽略 FallbackPage，
+	// 错误页面是 html/template 模板，可以使用失败的地址和原因，通过 window.desktop.retry() 重试
+	Fallback *FallbackOptions
+	// 窗口的所有者，必须是 New 或者 App.New 创建的窗口，窗口总是显示在所有者上面，
+	// 所有者最小化或者关闭时一起最小化或者关闭
+	Parent WebView
+	// 是否是模态窗口，需要同时设置 Parent，窗口打开期间所有者被禁用，
+	// 通过 ShowModal 显示并等待页面调用 window.desktop.close(result) 返回结果
+	Modal bool
+	// 是否记住窗口的位置、大小、最大化和全屏状态，关闭时保存到 DataPath 下，下次打开时恢复，
+	// 通过 App.New 创建的窗口按窗口 id 分别保存
+	PersistState bool
 }
 
 //go:embed desktop.ico
@@ -93,6 +291,46 @@ type WebView interface {
 	// SetTitle 设置窗口的标题文字
 	SetTitle(title string)
 
+	// SetSize 设置窗口内容区域的大小，hint 为 HintFixed 时用户不能调整大小，
+	// 为 HintMin、HintMax 时设置的是用户调整大小时的最小值、最大值
+	SetSize(width int, height int, hint Hint)
+
+	// SetMinSize 设置用户调整窗口大小时的最小值，等同于 SetSize(width, height, HintMin)
+	SetMinSize(width int, height int)
+
+	// SetMaxSize 设置用户调整窗口大小时的最大值，等同于 SetSize(width, height, HintMax)
+	SetMaxSize(width int, height int)
+
+	// GetSize 返回窗口内容区域的大小
+	GetSize() (width int, height int)
+
+	// SetPosition 把窗口移动到屏幕坐标 x, y
+	SetPosition(x int, y int)
+
+	// GetPosition 返回窗口左上角的屏幕坐标
+	GetPosition() (x int, y int)
+
+	// Minimize 最小化窗口
+	Minimize()
+
+	// Maximize 最大化窗口
+	Maximize()
+
+	// Restore 把最小化或者最大化的窗口恢复为普通状态
+	Restore()
+
+	// IsMaximized 返回窗口是否最大化
+	IsMaximized() bool
+
+	// SetFullscreen 全屏显示窗口或者退出全屏，全屏时覆盖窗口所在的整个显示器，退出时恢复之前的位置和大小
+	SetFullscreen(fullscreen bool)
+
+	// Center 把窗口移动到所在显示器的中间
+	Center()
+
+	// SetAlwaysOnTop 设置窗口是否总是显示在其他窗口上面
+	SetAlwaysOnTop(onTop bool)
+
 	// Navigate webview窗口跳转到指定url
 	Navigate(url string)
 
@@ -100,18 +338,129 @@ type WebView interface {
 	SetHtml(html string)
 
 	// Init 在页面初始化的时候注入的js代码，页面无论是跳转还是刷新后都会重新执行 Init 注入的代码
-	// 触发的时机会在 window.onload 之前
-	Init(js string)
+	// 触发的时机会在 window.onload 之前，
+	// 返回的 id 可以用于 RemoveInit 移除注入的代码
+	Init(js string) ScriptID
+
+	// RemoveInit 移除 Init 注入的代码，之后打开的页面不再执行，对当前页面没有影响
+	RemoveInit(id ScriptID)
 
 	// Eval 在webview页面执行js代码
 	Eval(js string)
 
+	// EvalAsync 在webview页面执行js代码，并返回表达式的值，JSON 格式，
+	// 如果值为 Promise，会等待 Promise resolve 后返回，reject 时返回 error，
+	// 最多等待 webview2.DefaultEvalTimeout
+	//
+	//  注: 结果需要 UI 线程处理，不能在 UI 线程（如 Dispatch 的函数）中调用
+	EvalAsync(js string) (json.RawMessage, error)
+
+	// EvalWithContext 和 EvalAsync 一样，但是由 ctx 控制超时和取消
+	EvalWithContext(ctx context.Context, js string) (json.RawMessage, error)
+
+	// CallJS 调用页面中的函数并返回结果，fn 是函数名，支持 a.b.c 形式的路径，
+	// args 会以 JSON 形式传给函数，函数返回 Promise 时会等待 resolve 后返回
+	CallJS(ctx context.Context, fn string, args ...interface{}) (json.RawMessage, error)
+
 	// Bind 注入JS函数，底层通过 Init 实现，用于往页面注入函数，实现 JS 和 Go 互相调用
 	// name 是函数名，
-	// fn 必须是 go 函数，否则无效，
+	// fn 必须是 go 函数，否则返回错误，
 	// 注入的函数调用后返回 Promise，在Promise resolve 获取go函数返回值，
-	// 注入的函数参数个数必须和js调用时传入的参数类型和个数一致，否则 reject
-	Bind(name string, f interface{})
+	// 注入的函数参数个数必须和js调用时传入的参数类型和个数一致，否则 reject，
+	// fn 第一个参数可以是 context.Context，页面跳转、窗口销毁、超时，
+	// 或者 js 调用时最后一个参数传入的 AbortSignal 触发 abort 时会被取消，
+	// fn 在独立的 goroutine 中执行，不会阻塞 UI 线程
+	Bind(name string, f interface{}, opts ...BindOption) error
+
+	// BindObject 把 obj 导出的方法绑定到页面的 window[ns] 对象下，
+	// 如 BindObject("fs", fs) 后页面中调用 window.fs.readFile() 即调用 fs.ReadFile，
+	// 方法的参数和返回值规则和 Bind 一致，opts 可以用 BindCase 设置方法名的命名方式，
+	// 用 BindExclude 排除不需要绑定的方法
+	BindObject(ns string, obj interface{}, opts ...BindOption) error
+
+	// Unbind 移除 Bind 绑定的函数，name 为 BindObject 的 ns 时移除该对象下所有的函数，
+	// 页面中对应的 JS 函数会被删除，已经在执行的调用不受影响
+	Unbind(name string)
+
+	// Handle 拦截页面中地址匹配 pattern 的请求，交给 handler 处理，可以使用任意的 Go 路由，
+	// handler 收到的请求包括方法、请求头和请求体，和普通 net/http 服务一样写入响应即可，
+	// pattern 中 * 匹配任意多个字符，? 匹配一个字符，如 https://api.example.com/*
+	Handle(pattern string, handler http.Handler)
+
+	// Use 注册拦截请求的中间件，注册后页面中的所有请求都会经过中间件，先注册的先执行，
+	// 中间件可以按地址和资源类型用 Filter 筛选请求，返回 Allow、Block、Redirect 或 Respond
+	Use(mw ResourceMiddleware)
+
+	// CacheStats 返回离线缓存的统计信息，没有开启离线缓存时返回零值
+	CacheStats() CacheStats
+
+	// OnNavigationStarting 监听页面开始跳转，fn 中可以通过 e.Cancel() 取消跳转，
+	// 以下监听函数都返回取消监听的函数，fn 在 UI 线程中执行，不能阻塞
+	OnNavigationStarting(fn func(e *NavigationStartingEvent)) func()
+
+	// OnNavigationCompleted 监听页面跳转完成，包括是否成功、失败原因和 HTTP 状态码
+	OnNavigationCompleted(fn func(e *NavigationCompletedEvent)) func()
+
+	// OnSourceChanged 监听页面地址变化
+	OnSourceChanged(fn func(e *SourceChangedEvent)) func()
+
+	// OnDOMContentLoaded 监听页面 DOMContentLoaded
+	OnDOMContentLoaded(fn func(e *DOMContentLoadedEvent)) func()
+
+	// OnTitleChanged 监听页面标题变化
+	OnTitleChanged(fn func(title string)) func()
+
+	// OnCloseRequested 监听用户关闭窗口，包括调用 Destroy，fn 返回 false 时取消关闭，如提示还有没保存的修改。
+	// 页面调用 window.desktop.close 时也会触发，App.Quit 关闭窗口时不会触发
+	OnCloseRequested(fn func() bool) func()
+
+	// OnFocus 监听窗口获得焦点，页面中为 window.desktop 上的 focus 事件
+	OnFocus(fn func()) func()
+
+	// OnBlur 监听窗口失去焦点，页面中为 window.desktop 上的 blur 事件
+	OnBlur(fn func()) func()
+
+	// OnResize 监听窗口内容区域大小变化，页面中为 window.desktop 上的 resize 事件，e.detail 为 {width, height}
+	OnResize(fn func(width int, height int)) func()
+
+	// OnMove 监听窗口移动，页面中为 window.desktop 上的 move 事件，e.detail 为 {x, y}
+	OnMove(fn func(x int, y int)) func()
+
+	// OnMinimize 监听窗口最小化，页面中为 window.desktop 上的 minimize 事件
+	OnMinimize(fn func()) func()
+
+	// OnRestore 监听窗口从最小化恢复，页面中为 window.desktop 上的 restore 事件
+	OnRestore(fn func()) func()
+
+	// ClearCache 删除离线缓存中的所有响应
+	ClearCache() error
+
+	// AddVirtualHost 把 https://host/ 映射到本地目录，页面可以通过稳定的 https 来源访问目录中的文件，
+	// host 为空或者目录不存在时返回错误，WebView2 Runtime 版本过低等在 UI 线程中发生的错误记录日志
+	AddVirtualHost(host string, vh VirtualHost) error
+
+	// RemoveVirtualHost 移除 AddVirtualHost 映射的目录，host 为空时返回错误
+	RemoveVirtualHost(host string) error
+
+	// Bindings 返回通过 Bind 绑定的 Go 函数，key 为页面中的函数名，
+	// 可以交给 bindgen 生成 TypeScript 类型声明
+	Bindings() map[string]interface{}
+
+	// Emit 向页面发送事件，页面中通过 window.desktop.on(event, cb) 监听，
+	// payload 会转成 JSON 传给 cb
+	Emit(event string, payload interface{}) error
+
+	// On 监听页面通过 window.desktop.emit(event, payload) 发送的事件，返回取消监听的函数，
+	// event 以 . 分隔，* 匹配任意一段，** 匹配任意多段，如 user.* 匹配 user.login，** 匹配所有事件，
+	// fn 在单独的 goroutine 中按事件顺序执行
+	On(event string, fn func(payload json.RawMessage)) func()
+
+	// Once 和 On 一样，但是只会触发一次
+	Once(event string, fn func(payload json.RawMessage)) func()
+
+	// ShowModal 显示窗口并等待窗口关闭，返回页面通过 window.desktop.close(result) 传入的值，
+	// 用户直接关闭窗口时返回 nil，一般在绑定的 Go 函数中调用，如打开登录或设置对话框
+	ShowModal() json.RawMessage
 
 	// Hide 隐藏窗口
 	Hide()
@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+
+	"github.com/eyasliu/desktop/go-webview2"
 )
 
 func iconBytesToFilePath(iconBytes []byte) (string, error) {
@@ -22,6 +24,52 @@ func iconBytesToFilePath(iconBytes []byte) (string, error) {
 	return iconFilePath, nil
 }
 
+// webviewOptions 把 Options 转换为 webview2 的配置，New、App.New 和 NewHeadless 共用，
+// 窗口图标只在 windows 下由 windowOptions 设置
+func webviewOptions(opt *Options) webview2.WebViewOptions {
+	wvOpts := webview2.WebViewOptions{
+		Debug:             opt.Debug,
+		StartURL:          opt.StartURL,
+		FallbackPage:      opt.FallbackPage,
+		DataPath:          opt.DataPath,
+		AutoFocus:         opt.AutoFocus,
+		HideWindowOnClose: opt.HideWindowOnClose,
+		Logger:            opt.Logger,
+		AllowedOrigins:    opt.AllowedOrigins,
+		Assets:            opt.Assets,
+		Handler:           opt.Handler,
+		VirtualHosts:      opt.VirtualHosts,
+		Cache:             opt.Cache,
+		NavigationPolicy:  opt.NavigationPolicy,
+		Fallback:          opt.Fallback,
+		Parent:            ownerOf(opt.Parent),
+		Modal:             opt.Modal,
+		PersistState:      opt.PersistState,
+		WindowOptions: webview2.WindowOptions{
+			Frameless:    opt.Frameless,
+			ResizeBorder: opt.ResizeBorder,
+			Title:        opt.Title,
+			Center:       opt.Center,
+			Width:        uint(opt.Width),
+			Height:       uint(opt.Height),
+			AlwaysOnTop:  opt.AlwaysOnTop,
+			StartHidden:  opt.StartHidden,
+		},
+	}
+	if wvOpts.Logger == nil {
+		wvOpts.Logger = &defaultLogger{}
+	}
+	return wvOpts
+}
+
+// ownerOf 返回 Options.Parent 对应的所有者窗口，不是 New 或者 App.New 创建的窗口时返回 nil
+func ownerOf(parent WebView) webview2.Owner {
+	if o, ok := parent.(webview2.Owner); ok {
+		return o
+	}
+	return nil
+}
+
 func IsHeadless() bool {
 	if len(os.Getenv("SSH_CONNECTION")) > 0 {
 		return true